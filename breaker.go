@@ -3,18 +3,61 @@ package breaker
 import (
 	"context"
 	stderrors "errors"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
 	"time"
 
 	"github.com/shuklasaharsh/circuitbreaker/storage"
 )
 
+// defaultSlowCallWindowSize is the count window used to track slow calls when
+// no window is configured.
+const defaultSlowCallWindowSize = 100
+
 // Breaker
 type Breaker struct {
-	Name             string
-	failureThreshold int64
-	successThreshold int64
-	timeout          time.Duration
-	store            storage.Store
+	Name                  string
+	failureThreshold      int64
+	successThreshold      int64
+	timeout               time.Duration
+	store                 storage.Store
+	window                window
+	slowCallWindow        window
+	throttle              *throttle
+	recoveryRamp          time.Duration
+	recoveryRampCurve     RampCurve
+	failureRateThreshold  float64
+	minimumCalls          int64
+	slowCallDuration      time.Duration
+	slowCallRateThreshold float64
+	halfOpenMaxCalls      int64
+	backoff               openBackoff
+	openJitter            time.Duration
+	classifier            func(error) Outcome
+	ignoredErrors         []func(error) bool
+	panicPolicy           PanicPolicy
+	callTimeout           time.Duration
+	onStateChange         func(name string, from, to State, record storage.Record)
+	fallback              func(context.Context, error) error
+	clock                 Clock
+	healthCheck           HealthChecker
+	healthCheckInterval   time.Duration
+	healthCheckTransition State
+	prober                *prober
+	logger                *slog.Logger
+	logLevels             LogLevels
+	rejectionLogInterval  time.Duration
+	rejections            rejectionLog
+
+	mu        sync.RWMutex
+	observers []*observer
+}
+
+type openBackoff struct {
+	initial    time.Duration
+	max        time.Duration
+	multiplier float64
 }
 
 func New(name string, opts ...Option) *Breaker {
@@ -28,13 +71,59 @@ func New(name string, opts ...Option) *Breaker {
 		cfg.Store = storage.NewMemoryStore()
 	}
 
-	return &Breaker{
-		Name:             name,
-		failureThreshold: cfg.FailureThreshold,
-		successThreshold: cfg.SuccessThreshold,
-		timeout:          cfg.Timeout,
-		store:            cfg.Store,
+	b := &Breaker{
+		Name:                  name,
+		failureThreshold:      cfg.FailureThreshold,
+		successThreshold:      cfg.SuccessThreshold,
+		timeout:               cfg.Timeout,
+		store:                 cfg.Store,
+		failureRateThreshold:  cfg.FailureRateThreshold,
+		minimumCalls:          cfg.MinimumCalls,
+		slowCallDuration:      cfg.SlowCallDuration,
+		slowCallRateThreshold: cfg.SlowCallRateThreshold,
+		halfOpenMaxCalls:      cfg.HalfOpenMaxCalls,
+		backoff: openBackoff{
+			initial:    cfg.OpenBackoffInitial,
+			max:        cfg.OpenBackoffMax,
+			multiplier: cfg.OpenBackoffMultiplier,
+		},
+		openJitter:    cfg.OpenJitter,
+		classifier:    cfg.ErrorClassifier,
+		ignoredErrors: cfg.IgnoredErrors,
+		panicPolicy:   cfg.PanicPolicy,
+		callTimeout:   cfg.CallTimeout,
+		onStateChange: cfg.OnStateChange,
+		fallback:      cfg.Fallback,
+		clock:         cfg.Clock,
+
+		healthCheck:           cfg.HealthCheck,
+		healthCheckInterval:   cfg.HealthCheckInterval,
+		healthCheckTransition: cfg.HealthCheckTransition,
+		logger:                cfg.Logger,
+		logLevels:             cfg.LogLevels,
+		rejectionLogInterval:  cfg.RejectionLogInterval,
+		recoveryRamp:          cfg.RecoveryRamp,
+		recoveryRampCurve:     cfg.RecoveryRampCurve,
+	}
+	if cfg.HealthCheck != nil {
+		b.prober = newProber()
 	}
+	switch {
+	case cfg.ThrottleK > 0:
+		b.throttle = newThrottle(cfg.ThrottleK, cfg.ThrottleWindow)
+		b.window = b.throttle.window
+	case cfg.SlidingWindowSize > 0:
+		b.window = countWindow{size: cfg.SlidingWindowSize}
+		b.minimumCalls = min(b.minimumCalls, cfg.SlidingWindowSize)
+	case cfg.TimeWindow > 0:
+		b.window = newTimeWindow(cfg.TimeWindow, cfg.TimeWindowBuckets)
+	case cfg.SlowCallDuration > 0:
+		// Failures still trip on the consecutive count; only slow calls are
+		// kept in a window.
+		b.slowCallWindow = countWindow{size: defaultSlowCallWindowSize}
+		b.minimumCalls = min(b.minimumCalls, defaultSlowCallWindowSize)
+	}
+	return b
 }
 
 // Execute runs the given function through the circuit breaker
@@ -44,34 +133,90 @@ func (b *Breaker) Execute(fn func() error) error {
 
 // ExecuteContext runs the given function through the circuit breaker with a context
 func (b *Breaker) ExecuteContext(ctx context.Context, fn func() error) error {
+	if fn == nil {
+		return ErrNilFunction
+	}
+	return b.ExecuteCtx(ctx, func(context.Context) error {
+		return fn()
+	})
+}
+
+// ExecuteCtx runs the given function through the circuit breaker and passes it
+// the context it should observe. With WithCallTimeout that context carries the
+// call deadline.
+func (b *Breaker) ExecuteCtx(ctx context.Context, fn func(context.Context) error) error {
+	return b.execute(ctx, fn, b.fallback)
+}
+
+// ExecuteWithFallback is like ExecuteCtx, but calls fallback instead of the
+// breaker's own fallback when the circuit is open or the call fails.
+func (b *Breaker) ExecuteWithFallback(ctx context.Context, fn func(context.Context) error, fallback func(context.Context, error) error) error {
+	if fallback == nil {
+		return ErrNilFunction
+	}
+	return b.execute(ctx, fn, fallback)
+}
+
+// execute runs fn through the breaker. When fallback is set it handles
+// ErrCircuitOpen and errors recorded as failures.
+func (b *Breaker) execute(ctx context.Context, fn func(context.Context) error, fallback func(context.Context, error) error) error {
 	// Validation
 	if fn == nil {
 		return ErrNilFunction
 	}
 
 	// Check if we can execute
-	allowed, err := b.allow(ctx)
+	permit, err := b.Allow(ctx)
 	if err != nil {
+		if fallback != nil && stderrors.Is(err, ErrCircuitOpen) {
+			return fallback(ctx, err)
+		}
 		return err
 	}
-	if !allowed {
-		return ErrCircuitOpen
-	}
+
+	callCtx, cancel := b.CallContext(ctx)
+	defer cancel()
 
 	// Execute the function
-	err = fn()
+	permit.start = b.clock.Now()
+	panicked, err := call(func() error {
+		return fn(callCtx)
+	})
 
 	// Record the result
+	outcome := b.outcome(ctx, callCtx, err)
+	recordErr := permit.finish(outcome, err)
+	if panicked && b.panicPolicy == PanicRepanic {
+		panic(err.(*PanicError).Value)
+	}
 	if err == nil {
-		return b.onSuccess(ctx)
+		return recordErr
 	}
-	recordErr := b.onFailure(ctx)
 	if recordErr != nil {
-		return stderrors.Join(err, recordErr)
+		err = stderrors.Join(err, recordErr)
+	}
+	if fallback != nil && outcome == OutcomeFailure {
+		return fallback(ctx, err)
 	}
 	return err
 }
 
+// CallContext returns the context a call admitted by Allow should observe:
+// ctx with the deadline set by WithCallTimeout, if any. cancel releases it
+// once the call returns.
+func (b *Breaker) CallContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if b.callTimeout > 0 {
+		return context.WithTimeout(ctx, b.callTimeout)
+	}
+	return ctx, func() {}
+}
+
+// PanicPolicy returns what the breaker does after recording a panic, so code
+// admitting calls through Allow can treat panics the same way.
+func (b *Breaker) PanicPolicy() PanicPolicy {
+	return b.panicPolicy
+}
+
 // NewDistributed returns a breaker backed by a shared storage engine.
 func NewDistributed(name string, store storage.Store, opts ...Option) *Breaker {
 	opts = append(opts, WithStorage(store))
@@ -82,7 +227,11 @@ func NewDistributed(name string, store storage.Store, opts ...Option) *Breaker {
 func (b *Breaker) Snapshot(ctx context.Context) (storage.Record, error) {
 	record, err := b.store.Load(ctx, b.Name)
 	if err == nil {
-		return normalizeRecord(record), nil
+		record = normalizeRecord(record)
+		if b.window != nil && (record.State == storage.StateClosed || record.State == storage.StateForcedClosed) {
+			b.refreshWindow(&record, b.clock.Now())
+		}
+		return record, nil
 	}
 	if stderrors.Is(err, storage.ErrNotFound) {
 		return storage.DefaultRecord(), nil
@@ -99,82 +248,335 @@ func (b *Breaker) State(ctx context.Context) (State, error) {
 	return record.State, nil
 }
 
+// ForceOpen pins the breaker open: every call is rejected with ErrCircuitOpen
+// until Reset. The override lives in the store, so it applies to every breaker
+// sharing it.
+func (b *Breaker) ForceOpen(ctx context.Context) error {
+	return b.override(ctx, storage.StateForcedOpen)
+}
+
+// ForceClose pins the breaker closed: every call is admitted and its outcome
+// recorded, but the breaker never trips until Reset.
+func (b *Breaker) ForceClose(ctx context.Context) error {
+	return b.override(ctx, storage.StateForcedClosed)
+}
+
+// Disable bypasses the breaker: every call is admitted and nothing is recorded
+// until Reset.
+func (b *Breaker) Disable(ctx context.Context) error {
+	return b.override(ctx, storage.StateDisabled)
+}
+
+// Reset clears any override and returns the breaker to a fresh closed record.
+func (b *Breaker) Reset(ctx context.Context) error {
+	return b.override(ctx, storage.StateClosed)
+}
+
+// override replaces the record with a fresh one in the given state.
+func (b *Breaker) override(ctx context.Context, state State) error {
+	_, err := b.update(ctx, func(record *storage.Record) {
+		*record = storage.DefaultRecord()
+		record.State = state
+	})
+	return err
+}
+
+// admission is the result of asking the breaker to let a call through.
+type admission struct {
+	// state is the state the call was admitted in.
+	state   State
+	allowed bool
+	record  storage.Record
+}
+
 // allow checks if a request can be executed and performs state transitions.
-func (b *Breaker) allow(ctx context.Context) (bool, error) {
-	var allowed bool
-	_, err := b.store.Update(ctx, b.Name, func(record storage.Record) (storage.Record, error) {
-		record = normalizeRecord(record)
+func (b *Breaker) allow(ctx context.Context) (admission, error) {
+	var adm admission
+	now := b.clock.Now()
+	record, err := b.update(ctx, func(record *storage.Record) {
+		adm.state = record.State
 		switch record.State {
 		case storage.StateClosed:
-			allowed = true
-			return record, nil
+			adm.allowed = b.admitRamp(record, now) && (b.throttle == nil || b.throttle.admit(record, now))
 		case storage.StateOpen:
-			if time.Since(record.LastFailureTime) > b.timeout {
-				record.State = storage.StateHalfOpen
-				record.Successes = 0
-				allowed = true
+			if now.After(b.openUntil(*record)) {
+				b.halfOpen(record)
+				b.grantTrial(record, now)
+				adm.state = storage.StateHalfOpen
+				adm.allowed = true
 			} else {
-				allowed = false
+				adm.allowed = false
 			}
-			return record, nil
 		case storage.StateHalfOpen:
-			allowed = true
-			return record, nil
-		default:
-			allowed = false
-			return storage.DefaultRecord(), nil
+			if b.halfOpenMaxCalls > 0 && record.HalfOpenCalls >= b.halfOpenMaxCalls {
+				// Trials that never reported, because their caller crashed or
+				// dropped the permit, must not hold the breaker half-open.
+				if !now.After(record.HalfOpenLease) {
+					adm.allowed = false
+					return
+				}
+				record.HalfOpenCalls = 0
+			}
+			b.grantTrial(record, now)
+			adm.allowed = true
+		case storage.StateForcedClosed, storage.StateDisabled:
+			adm.allowed = true
+		case storage.StateForcedOpen:
+			adm.allowed = false
 		}
 	})
-	return allowed, err
+	adm.record = record
+	return adm, err
 }
 
-// onSuccess handles a successful execution.
-func (b *Breaker) onSuccess(ctx context.Context) error {
-	_, err := b.store.Update(ctx, b.Name, func(record storage.Record) (storage.Record, error) {
-		record = normalizeRecord(record)
+// onSuccess handles a successful execution admitted in the given state.
+func (b *Breaker) onSuccess(ctx context.Context, admitted State, slow bool) (storage.Record, error) {
+	now := b.clock.Now()
+	return b.update(ctx, func(record *storage.Record) {
+		releaseHalfOpenCall(record, admitted)
 		switch record.State {
 		case storage.StateClosed:
+			if b.window != nil {
+				b.window.add(record, callResult(storage.CallSuccess, slow), now)
+				b.evaluateWindow(record, now)
+				return
+			}
 			record.Failures = 0
 			record.Successes = 0
+			b.evaluateSlowCalls(record, callResult(storage.CallSuccess, slow), now)
 		case storage.StateHalfOpen:
 			record.Successes++
 			if record.Successes >= b.successThreshold {
-				record.State = storage.StateClosed
-				record.Failures = 0
-				record.Successes = 0
+				b.close(record, now)
+			}
+		case storage.StateForcedClosed:
+			if b.window != nil {
+				b.window.add(record, callResult(storage.CallSuccess, slow), now)
+				b.refreshWindow(record, now)
+				return
 			}
+			record.Failures = 0
+			record.Successes = 0
 		}
-		return record, nil
 	})
-	return err
 }
 
-// onFailure handles a failed execution.
-func (b *Breaker) onFailure(ctx context.Context) error {
-	now := time.Now()
-	_, err := b.store.Update(ctx, b.Name, func(record storage.Record) (storage.Record, error) {
-		record = normalizeRecord(record)
+// onFailure handles a failed execution admitted in the given state.
+func (b *Breaker) onFailure(ctx context.Context, admitted State, slow bool) (storage.Record, error) {
+	now := b.clock.Now()
+	return b.update(ctx, func(record *storage.Record) {
+		releaseHalfOpenCall(record, admitted)
 		record.LastFailureTime = now
 
 		switch record.State {
 		case storage.StateClosed:
+			if b.window != nil {
+				b.window.add(record, callResult(storage.CallFailure, slow), now)
+				b.evaluateWindow(record, now)
+				return
+			}
 			record.Failures++
 			record.Successes = 0
 			if record.Failures >= b.failureThreshold {
-				record.State = storage.StateOpen
+				b.trip(record, now)
+				return
 			}
+			b.evaluateSlowCalls(record, callResult(storage.CallFailure, slow), now)
 		case storage.StateHalfOpen:
-			record.State = storage.StateOpen
+			b.trip(record, now)
+		case storage.StateForcedClosed:
+			if b.window != nil {
+				b.window.add(record, callResult(storage.CallFailure, slow), now)
+				b.refreshWindow(record, now)
+				return
+			}
+			record.Failures++
 			record.Successes = 0
 		}
+	})
+}
+
+// onIgnore handles an execution whose outcome is not recorded. Only the trial
+// permit of a call admitted while half-open is returned.
+func (b *Breaker) onIgnore(ctx context.Context, admitted State) (storage.Record, error) {
+	if admitted != storage.StateHalfOpen {
+		return storage.Record{}, nil
+	}
+	return b.update(ctx, func(record *storage.Record) {
+		releaseHalfOpenCall(record, admitted)
+	})
+}
+
+// update applies fn to the normalized record through the store. Once the store
+// has committed the update, a state change it caused is announced.
+func (b *Breaker) update(ctx context.Context, fn func(record *storage.Record)) (storage.Record, error) {
+	var from State
+	updated, err := b.store.Update(ctx, b.Name, func(record storage.Record) (storage.Record, error) {
+		record = normalizeRecord(record)
+		from = record.State
+		fn(&record)
 		return record, nil
 	})
-	return err
+	if err != nil {
+		b.logStoreError(ctx, err)
+		return storage.Record{}, err
+	}
+	if from != updated.State {
+		b.logStateChange(ctx, from, updated)
+		b.emitStateChange(ctx, from, updated)
+	}
+	if updated.State == storage.StateOpen {
+		b.startProbe()
+	}
+	return updated, nil
+}
+
+// evaluateWindow refreshes the windowed counters of a closed record and opens
+// it once the failure or slow call rate reaches its threshold. A throttling
+// breaker never opens.
+func (b *Breaker) evaluateWindow(record *storage.Record, now time.Time) {
+	stats := b.refreshWindow(record, now)
+	if b.throttle != nil || stats.calls < b.minimumCalls {
+		return
+	}
+	tripped := stats.failureRate() >= b.failureRateThreshold
+	if b.slowCallDuration > 0 && stats.slowCallRate() >= b.slowCallRateThreshold {
+		tripped = true
+	}
+	if tripped {
+		b.trip(record, now)
+	}
+}
+
+// evaluateSlowCalls tracks the calls of a breaker without a window in its slow
+// call window and opens the record once the slow call rate reaches its
+// threshold.
+func (b *Breaker) evaluateSlowCalls(record *storage.Record, result storage.CallResult, now time.Time) {
+	if b.slowCallWindow == nil {
+		return
+	}
+	b.slowCallWindow.add(record, result, now)
+	stats := b.slowCallWindow.stats(*record, now)
+	record.SlowCalls = stats.slow
+	if stats.calls >= b.minimumCalls && stats.slowCallRate() >= b.slowCallRateThreshold {
+		b.trip(record, now)
+	}
+}
+
+// trip opens the record. Consecutive trips without a close in between are
+// counted so the open duration can back off.
+func (b *Breaker) trip(record *storage.Record, now time.Time) {
+	record.State = storage.StateOpen
+	record.Successes = 0
+	record.HalfOpenCalls = 0
+	record.HalfOpenLease = time.Time{}
+	record.LastFailureTime = now
+	record.Trips++
+	record.OpenUntil = now.Add(b.openDuration(record.Trips) + b.jitter())
+	b.resetWindow(record)
+}
+
+// halfOpen moves the record to half-open to await trial calls.
+func (b *Breaker) halfOpen(record *storage.Record) {
+	record.State = storage.StateHalfOpen
+	record.Successes = 0
+	record.HalfOpenCalls = 0
+	record.HalfOpenLease = time.Time{}
+	record.OpenUntil = time.Time{}
+}
+
+// grantTrial hands out a half-open trial permit. The permits in flight are
+// reclaimed if none reports within an open period of the latest grant.
+func (b *Breaker) grantTrial(record *storage.Record, now time.Time) {
+	record.HalfOpenCalls++
+	record.HalfOpenLease = now.Add(max(b.openDuration(record.Trips), b.callTimeout))
+}
+
+// close returns the record to the closed state and clears the trip count.
+// With WithRecoveryRamp the ramp starts at now.
+func (b *Breaker) close(record *storage.Record, now time.Time) {
+	record.State = storage.StateClosed
+	record.Failures = 0
+	record.Successes = 0
+	record.HalfOpenCalls = 0
+	record.HalfOpenLease = time.Time{}
+	record.Trips = 0
+	record.OpenUntil = time.Time{}
+	record.RampStart = time.Time{}
+	if b.recoveryRamp > 0 {
+		record.RampStart = now
+	}
+	b.resetWindow(record)
+}
+
+// openDuration returns how long the breaker stays open after the given number
+// of consecutive trips.
+func (b *Breaker) openDuration(trips int64) time.Duration {
+	if b.backoff.initial <= 0 {
+		return b.timeout
+	}
+	d := b.backoff.initial
+	for i := int64(1); i < trips && d < b.backoff.max; i++ {
+		d = time.Duration(float64(d) * b.backoff.multiplier)
+	}
+	return min(d, b.backoff.max)
+}
+
+// jitter returns a random extension of the open duration in [0, openJitter).
+func (b *Breaker) jitter() time.Duration {
+	if b.openJitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(b.openJitter)))
+}
+
+// openUntil returns when an open record may move to half-open. Records written
+// before the deadline was stored fall back to the last failure time.
+func (b *Breaker) openUntil(record storage.Record) time.Time {
+	if !record.OpenUntil.IsZero() {
+		return record.OpenUntil
+	}
+	return record.LastFailureTime.Add(b.openDuration(record.Trips))
+}
+
+// refreshWindow sets the record counters to the calls still inside the window.
+func (b *Breaker) refreshWindow(record *storage.Record, now time.Time) windowStats {
+	stats := b.window.stats(*record, now)
+	record.Failures = stats.failures
+	record.Successes = stats.calls - stats.failures
+	record.SlowCalls = stats.slow
+	return stats
+}
+
+// releaseHalfOpenCall returns the trial permit held by a call admitted while
+// the breaker was half-open.
+func releaseHalfOpenCall(record *storage.Record, admitted State) {
+	if admitted == storage.StateHalfOpen && record.State == storage.StateHalfOpen && record.HalfOpenCalls > 0 {
+		record.HalfOpenCalls--
+	}
+}
+
+func callResult(result storage.CallResult, slow bool) storage.CallResult {
+	if slow {
+		result |= storage.CallSlow
+	}
+	return result
+}
+
+func (b *Breaker) resetWindow(record *storage.Record) {
+	if b.window != nil {
+		b.window.reset(record)
+	}
+	if b.slowCallWindow != nil {
+		b.slowCallWindow.reset(record)
+		record.SlowCalls = 0
+	}
 }
 
 func normalizeRecord(record storage.Record) storage.Record {
 	switch record.State {
-	case storage.StateClosed, storage.StateOpen, storage.StateHalfOpen:
+	case storage.StateClosed, storage.StateOpen, storage.StateHalfOpen,
+		storage.StateForcedOpen, storage.StateForcedClosed, storage.StateDisabled:
 		return record
 	default:
 		return storage.DefaultRecord()
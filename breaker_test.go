@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/shuklasaharsh/circuitbreaker/breakertest"
 	"github.com/shuklasaharsh/circuitbreaker/storage"
 )
 
@@ -188,3 +189,624 @@ func TestExecuteJoinsUpdateError(t *testing.T) {
 		t.Fatalf("expected joined errors, got %v", err)
 	}
 }
+
+func TestExecuteSlidingWindowTripsOnFailureRate(t *testing.T) {
+	b := New("svc",
+		WithSlidingWindow(10),
+		WithFailureRateThreshold(40),
+		WithTimeout(time.Minute),
+	)
+	boom := errors.New("boom")
+	// Fail two of every five calls: never five in a row, but a 40% rate.
+	for i := 0; i < 10; i++ {
+		var err error
+		if i%5 < 2 {
+			err = b.Execute(func() error { return boom })
+		} else {
+			err = b.Execute(func() error { return nil })
+		}
+		if errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call %d rejected before window filled", i)
+		}
+	}
+
+	state, err := b.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != StateOpen {
+		t.Fatalf("expected open at 40%% failure rate, got %v", state)
+	}
+}
+
+func TestExecuteSlidingWindowBelowRateStaysClosed(t *testing.T) {
+	b := New("svc",
+		WithSlidingWindow(4),
+		WithFailureRateThreshold(50),
+	)
+	boom := errors.New("boom")
+	for i := 0; i < 12; i++ {
+		if i%4 == 0 {
+			_ = b.Execute(func() error { return boom })
+			continue
+		}
+		_ = b.Execute(func() error { return nil })
+	}
+
+	record, err := b.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if record.State != StateClosed {
+		t.Fatalf("expected closed at 25%% failure rate, got %v", record.State)
+	}
+	if len(record.Calls) != 4 {
+		t.Fatalf("expected window of 4 calls, got %d", len(record.Calls))
+	}
+	if record.Failures != 1 || record.Successes != 3 {
+		t.Fatalf("expected failures=1 successes=3, got failures=%d successes=%d", record.Failures, record.Successes)
+	}
+}
+
+func TestExecuteSlidingWindowRespectsMinimumCalls(t *testing.T) {
+	b := New("svc",
+		WithSlidingWindow(20),
+		WithMinimumCalls(3),
+	)
+	_ = b.Execute(func() error { return errors.New("boom") })
+	_ = b.Execute(func() error { return errors.New("boom") })
+
+	state, err := b.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != StateClosed {
+		t.Fatalf("expected closed below minimum calls, got %v", state)
+	}
+
+	_ = b.Execute(func() error { return errors.New("boom") })
+	state, err = b.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != StateOpen {
+		t.Fatalf("expected open after minimum calls, got %v", state)
+	}
+}
+
+func TestExecuteTimeWindowTripsOnFailureRate(t *testing.T) {
+	b := New("svc",
+		WithTimeWindow(time.Minute, 60),
+		WithMinimumCalls(4),
+		WithTimeout(time.Minute),
+	)
+	boom := errors.New("boom")
+	_ = b.Execute(func() error { return nil })
+	_ = b.Execute(func() error { return boom })
+	_ = b.Execute(func() error { return nil })
+	_ = b.Execute(func() error { return boom })
+
+	state, err := b.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != StateOpen {
+		t.Fatalf("expected open at 50%% failure rate, got %v", state)
+	}
+}
+
+func TestSnapshotTimeWindowExpiresFailures(t *testing.T) {
+	span := 20 * time.Second
+	clock := breakertest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := New("svc",
+		WithTimeWindow(span, 2),
+		WithMinimumCalls(5),
+		WithClock(clock),
+	)
+	_ = b.Execute(func() error { return errors.New("boom") })
+
+	record, err := b.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if record.Failures != 1 {
+		t.Fatalf("expected 1 failure in window, got %d", record.Failures)
+	}
+
+	clock.Advance(2 * span)
+	record, err = b.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if record.Failures != 0 {
+		t.Fatalf("expected failures to age out, got %d", record.Failures)
+	}
+}
+
+func TestExecuteSlowCallsTripBreaker(t *testing.T) {
+	b := New("svc",
+		WithSlidingWindow(4),
+		WithSlowCallDuration(time.Millisecond),
+		WithSlowCallRateThreshold(50),
+		WithTimeout(time.Minute),
+	)
+	slowCall := func() error {
+		time.Sleep(3 * time.Millisecond)
+		return nil
+	}
+	_ = b.Execute(slowCall)
+	_ = b.Execute(func() error { return nil })
+	_ = b.Execute(func() error { return nil })
+
+	record, err := b.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if record.State != StateClosed || record.SlowCalls != 1 {
+		t.Fatalf("expected closed with 1 slow call, got %v with %d", record.State, record.SlowCalls)
+	}
+
+	if err := b.Execute(slowCall); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	state, err := b.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != StateOpen {
+		t.Fatalf("expected open at 50%% slow call rate, got %v", state)
+	}
+}
+
+func TestExecuteSlowCallDurationUsesDefaultWindow(t *testing.T) {
+	b := New("svc", WithSlowCallDuration(time.Hour))
+	if _, ok := b.slowCallWindow.(countWindow); !ok || b.window != nil {
+		t.Fatalf("expected only a slow call window, got %T and %T", b.slowCallWindow, b.window)
+	}
+	_ = b.Execute(func() error { return nil })
+
+	record, err := b.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if record.SlowCalls != 0 || len(record.Calls) != 1 {
+		t.Fatalf("expected one fast call tracked, got slow=%d calls=%d", record.SlowCalls, len(record.Calls))
+	}
+}
+
+func TestExecuteSlowCallDurationKeepsFailureThreshold(t *testing.T) {
+	b := New("svc",
+		WithSlowCallDuration(time.Hour),
+		WithFailureThreshold(3),
+		WithTimeout(time.Minute),
+	)
+	for range 3 {
+		_ = b.Execute(func() error { return errors.New("boom") })
+	}
+	state, err := b.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != StateOpen {
+		t.Fatalf("expected consecutive failures to trip, got %v", state)
+	}
+}
+
+func TestExecuteSlowCallsTripWithoutWindow(t *testing.T) {
+	clock := breakertest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := New("svc",
+		WithSlowCallDuration(time.Second),
+		WithSlowCallRateThreshold(50),
+		WithMinimumCalls(2),
+		WithTimeout(time.Minute),
+		WithClock(clock),
+	)
+	slowCall := func() error {
+		clock.Advance(2 * time.Second)
+		return nil
+	}
+	_ = b.Execute(func() error { return nil })
+	_ = b.Execute(slowCall)
+
+	state, err := b.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != StateOpen {
+		t.Fatalf("expected open at 50%% slow call rate, got %v", state)
+	}
+}
+
+func TestExecuteHalfOpenMaxCallsLimitsProbes(t *testing.T) {
+	timeout := 5 * time.Millisecond
+	store := storage.NewMemoryStore()
+	opts := []Option{
+		WithFailureThreshold(1),
+		WithSuccessThreshold(2),
+		WithTimeout(timeout),
+		WithHalfOpenMaxCalls(1),
+	}
+	first := NewDistributed("svc", store, opts...)
+	second := NewDistributed("svc", store, opts...)
+	_ = first.Execute(func() error { return errors.New("boom") })
+	time.Sleep(timeout + time.Millisecond)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- first.Execute(func() error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	if err := second.Execute(func() error { return nil }); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen while probe in flight, got %v", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("probe error: %v", err)
+	}
+	if err := second.Execute(func() error { return nil }); err != nil {
+		t.Fatalf("expected permit after probe finished, got %v", err)
+	}
+	state, err := first.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != StateClosed {
+		t.Fatalf("expected closed after successful probes, got %v", state)
+	}
+}
+
+func TestOpenDurationBacksOff(t *testing.T) {
+	b := New("svc", WithOpenBackoff(time.Second, 10*time.Second, 2))
+	cases := []struct {
+		trips    int64
+		expected time.Duration
+	}{
+		{0, time.Second},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{50, 10 * time.Second},
+	}
+	for _, tc := range cases {
+		if got := b.openDuration(tc.trips); got != tc.expected {
+			t.Fatalf("trips=%d: expected %v, got %v", tc.trips, tc.expected, got)
+		}
+	}
+}
+
+func TestOpenDurationWithoutBackoffUsesTimeout(t *testing.T) {
+	b := New("svc", WithTimeout(3*time.Second))
+	if got := b.openDuration(7); got != 3*time.Second {
+		t.Fatalf("expected timeout, got %v", got)
+	}
+}
+
+func TestExecuteOpenBackoffLengthensRepeatedTrips(t *testing.T) {
+	initial := 5 * time.Second
+	clock := breakertest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := New("svc",
+		WithFailureThreshold(1),
+		WithSuccessThreshold(1),
+		WithOpenBackoff(initial, time.Minute, 10),
+		WithClock(clock),
+	)
+	boom := errors.New("boom")
+	_ = b.Execute(func() error { return boom })
+
+	clock.Advance(initial + time.Millisecond)
+	_ = b.Execute(func() error { return boom })
+
+	record, err := b.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if record.State != StateOpen || record.Trips != 2 {
+		t.Fatalf("expected open after 2 trips, got %v after %d", record.State, record.Trips)
+	}
+
+	clock.Advance(initial + time.Millisecond)
+	if err := b.Execute(func() error { return nil }); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected backed-off open circuit, got %v", err)
+	}
+
+	clock.Advance(10 * initial)
+	if err := b.Execute(func() error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	record, err = b.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if record.State != StateClosed || record.Trips != 0 {
+		t.Fatalf("expected closed with trips reset, got %v with %d", record.State, record.Trips)
+	}
+}
+
+func TestTripStoresJitteredOpenUntil(t *testing.T) {
+	timeout := time.Second
+	jitter := 500 * time.Millisecond
+	b := New("svc",
+		WithFailureThreshold(1),
+		WithTimeout(timeout),
+		WithOpenJitter(jitter),
+	)
+	before := time.Now()
+	_ = b.Execute(func() error { return errors.New("boom") })
+	after := time.Now()
+
+	record, err := b.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	earliest := before.Add(timeout)
+	latest := after.Add(timeout + jitter)
+	if record.OpenUntil.Before(earliest) || !record.OpenUntil.Before(latest) {
+		t.Fatalf("expected open until in [%v, %v), got %v", earliest, latest, record.OpenUntil)
+	}
+}
+
+func TestAllowUsesStoredOpenUntil(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	deadline := time.Now().Add(5 * time.Millisecond)
+	if err := store.Save(ctx, "svc", storage.Record{
+		State:           storage.StateOpen,
+		LastFailureTime: time.Now(),
+		OpenUntil:       deadline,
+	}); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+
+	// The local timeout is far longer, but the shared deadline wins.
+	b := NewDistributed("svc", store, WithTimeout(time.Hour))
+	if err := b.Execute(func() error { return nil }); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen before deadline, got %v", err)
+	}
+	time.Sleep(time.Until(deadline) + time.Millisecond)
+	if err := b.Execute(func() error { return nil }); err != nil {
+		t.Fatalf("expected probe after deadline, got %v", err)
+	}
+}
+
+func TestExecuteCtxPassesContext(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+	b := New("svc")
+	err := b.ExecuteCtx(ctx, func(ctx context.Context) error {
+		if ctx.Value(key{}) != "value" {
+			t.Fatalf("expected caller context values")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestExecuteCtxNilFunction(t *testing.T) {
+	b := New("svc")
+	if err := b.ExecuteCtx(context.Background(), nil); !errors.Is(err, ErrNilFunction) {
+		t.Fatalf("expected ErrNilFunction, got %v", err)
+	}
+}
+
+func TestExecuteCtxCallTimeoutCountsAsFailure(t *testing.T) {
+	b := New("svc",
+		WithFailureThreshold(1),
+		WithCallTimeout(time.Millisecond),
+		WithTimeout(time.Minute),
+	)
+	err := b.ExecuteCtx(context.Background(), func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Fatalf("expected call deadline")
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+
+	state, err := b.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != StateOpen {
+		t.Fatalf("expected open after call timeout, got %v", state)
+	}
+}
+
+func TestExecuteCtxCallerCancellationIgnored(t *testing.T) {
+	b := New("svc", WithFailureThreshold(1))
+	ctx, cancel := context.WithCancel(context.Background())
+	err := b.ExecuteCtx(ctx, func(ctx context.Context) error {
+		cancel()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Canceled, got %v", err)
+	}
+
+	record, err := b.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if record.State != StateClosed || record.Failures != 0 {
+		t.Fatalf("expected cancellation to be ignored, got %v with %d failures", record.State, record.Failures)
+	}
+}
+
+func TestForceOpenAppliesAcrossSharedStore(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	var transitions []State
+	first := NewDistributed("svc", store, WithOnStateChange(func(_ string, _, to State, _ storage.Record) {
+		transitions = append(transitions, to)
+	}))
+	second := NewDistributed("svc", store)
+
+	if err := first.ForceOpen(ctx); err != nil {
+		t.Fatalf("force open error: %v", err)
+	}
+	if err := second.Execute(func() error { return nil }); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	state, err := second.State(ctx)
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != StateForcedOpen {
+		t.Fatalf("expected forced open, got %v", state)
+	}
+
+	if err := first.Reset(ctx); err != nil {
+		t.Fatalf("reset error: %v", err)
+	}
+	if err := second.Execute(func() error { return nil }); err != nil {
+		t.Fatalf("expected call after reset, got %v", err)
+	}
+	if len(transitions) != 2 || transitions[0] != StateForcedOpen || transitions[1] != StateClosed {
+		t.Fatalf("expected forced open then closed transitions, got %v", transitions)
+	}
+}
+
+func TestForceOpenIgnoresTimeout(t *testing.T) {
+	timeout := time.Millisecond
+	b := New("svc", WithTimeout(timeout))
+	if err := b.ForceOpen(context.Background()); err != nil {
+		t.Fatalf("force open error: %v", err)
+	}
+	time.Sleep(timeout + time.Millisecond)
+	if err := b.Execute(func() error { return nil }); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+}
+
+func TestForceCloseRecordsWithoutTripping(t *testing.T) {
+	ctx := context.Background()
+	b := New("svc", WithFailureThreshold(1))
+	if err := b.ForceClose(ctx); err != nil {
+		t.Fatalf("force close error: %v", err)
+	}
+	boom := errors.New("boom")
+	for i := 0; i < 3; i++ {
+		if err := b.Execute(func() error { return boom }); !errors.Is(err, boom) {
+			t.Fatalf("expected call error, got %v", err)
+		}
+	}
+
+	record, err := b.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if record.State != StateForcedClosed || record.Failures != 3 {
+		t.Fatalf("expected forced closed with 3 failures, got %v with %d", record.State, record.Failures)
+	}
+}
+
+func TestDisableSkipsRecording(t *testing.T) {
+	ctx := context.Background()
+	b := New("svc", WithFailureThreshold(1))
+	_ = b.Execute(func() error { return errors.New("boom") })
+	if err := b.Disable(ctx); err != nil {
+		t.Fatalf("disable error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		_ = b.Execute(func() error { return errors.New("boom") })
+	}
+
+	record, err := b.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if record.State != StateDisabled || record.Failures != 0 {
+		t.Fatalf("expected disabled with no failures, got %v with %d", record.State, record.Failures)
+	}
+}
+
+func TestResetClosesOpenBreaker(t *testing.T) {
+	ctx := context.Background()
+	b := New("svc", WithFailureThreshold(1), WithTimeout(time.Minute))
+	_ = b.Execute(func() error { return errors.New("boom") })
+	if err := b.Reset(ctx); err != nil {
+		t.Fatalf("reset error: %v", err)
+	}
+	record, err := b.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if record.State != StateClosed || record.Trips != 0 || record.Failures != 0 {
+		t.Fatalf("expected fresh closed record, got %#v", record)
+	}
+}
+
+func TestWithFallbackOnOpenAndFailure(t *testing.T) {
+	callErr := errors.New("boom")
+	var got []error
+	b := New("svc",
+		WithFailureThreshold(1),
+		WithTimeout(time.Minute),
+		WithFallback(func(_ context.Context, err error) error {
+			got = append(got, err)
+			return nil
+		}),
+	)
+
+	if err := b.Execute(func() error { return callErr }); err != nil {
+		t.Fatalf("expected fallback to handle failure, got %v", err)
+	}
+	if err := b.Execute(func() error { return nil }); err != nil {
+		t.Fatalf("expected fallback to handle rejection, got %v", err)
+	}
+	if len(got) != 2 || !errors.Is(got[0], callErr) || !errors.Is(got[1], ErrCircuitOpen) {
+		t.Fatalf("expected failure then rejection in fallback, got %v", got)
+	}
+}
+
+func TestWithFallbackSkipsIgnoredErrors(t *testing.T) {
+	ignored := errors.New("not found")
+	called := false
+	b := New("svc",
+		WithIgnoredErrors(ignored),
+		WithFallback(func(context.Context, error) error {
+			called = true
+			return nil
+		}),
+	)
+
+	if err := b.Execute(func() error { return ignored }); !errors.Is(err, ignored) {
+		t.Fatalf("expected ignored error, got %v", err)
+	}
+	if called {
+		t.Fatalf("fallback should not run for ignored errors")
+	}
+}
+
+func TestExecuteWithFallbackOverridesBreakerFallback(t *testing.T) {
+	b := New("svc", WithFallback(func(context.Context, error) error {
+		t.Fatalf("breaker fallback should not run")
+		return nil
+	}))
+	degraded := errors.New("degraded")
+
+	err := b.ExecuteWithFallback(context.Background(),
+		func(context.Context) error { return errors.New("boom") },
+		func(context.Context, error) error { return degraded },
+	)
+	if err != degraded {
+		t.Fatalf("expected per-call fallback result, got %v", err)
+	}
+	if err := b.ExecuteWithFallback(context.Background(), func(context.Context) error { return nil }, nil); !errors.Is(err, ErrNilFunction) {
+		t.Fatalf("expected ErrNilFunction, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+// Package breakertest provides helpers for testing code that uses circuit
+// breakers.
+package breakertest
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a clock that only moves when told to. It satisfies
+// breaker.Clock and storage.Clock, and is safe for concurrent use.
+//
+// FakeClock starts no goroutines or timers, so it can also be used inside a
+// testing/synctest bubble.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a clock stopped at start.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set moves the clock to t.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
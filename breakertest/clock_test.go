@@ -0,0 +1,76 @@
+package breakertest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	breaker "github.com/shuklasaharsh/circuitbreaker"
+)
+
+func TestFakeClockAdvanceAndSet(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	if !clock.Now().Equal(start) {
+		t.Fatalf("expected %v, got %v", start, clock.Now())
+	}
+	clock.Advance(time.Minute)
+	if got := clock.Now(); !got.Equal(start.Add(time.Minute)) {
+		t.Fatalf("expected clock to advance, got %v", got)
+	}
+	clock.Set(start)
+	if !clock.Now().Equal(start) {
+		t.Fatalf("expected clock to be set back, got %v", clock.Now())
+	}
+}
+
+func TestFakeClockDrivesOpenTimeout(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithTimeout(time.Minute),
+		breaker.WithClock(clock),
+	)
+	_ = b.Execute(func() error { return errors.New("boom") })
+
+	if err := b.Execute(func() error { return nil }); !errors.Is(err, breaker.ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	clock.Advance(time.Minute + time.Nanosecond)
+	permit, err := b.Allow(context.Background())
+	if err != nil {
+		t.Fatalf("expected trial call after timeout, got %v", err)
+	}
+	if permit.State() != breaker.StateHalfOpen {
+		t.Fatalf("expected half-open permit, got %v", permit.State())
+	}
+}
+
+func TestBreakerInsideSynctestBubble(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		clock := NewFakeClock(time.Now())
+		b := breaker.New("svc",
+			breaker.WithFailureThreshold(1),
+			breaker.WithTimeout(time.Minute),
+		)
+		fake := breaker.New("fake",
+			breaker.WithFailureThreshold(1),
+			breaker.WithTimeout(time.Minute),
+			breaker.WithClock(clock),
+		)
+		_ = b.Execute(func() error { return errors.New("boom") })
+		_ = fake.Execute(func() error { return errors.New("boom") })
+
+		// The bubble's clock jumps forward instead of sleeping for real.
+		time.Sleep(time.Minute + time.Nanosecond)
+		clock.Advance(time.Minute + time.Nanosecond)
+
+		for _, cb := range []*breaker.Breaker{b, fake} {
+			if err := cb.Execute(func() error { return nil }); err != nil {
+				t.Fatalf("%s: expected trial call after timeout, got %v", cb.Name, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,134 @@
+// Package bulkhead limits the number of concurrent calls to a dependency, so
+// a slow dependency cannot pile up goroutines without bound.
+package bulkhead
+
+import (
+	"context"
+	stderrors "errors"
+	"sync/atomic"
+	"time"
+
+	breaker "github.com/shuklasaharsh/circuitbreaker"
+)
+
+type Bulkhead struct {
+	Name            string
+	slots           chan struct{}
+	maxQueue        int64
+	maxWait         time.Duration
+	breaker         *breaker.Breaker
+	countRejections bool
+	waiting         atomic.Int64
+}
+
+func New(name string, opts ...Option) *Bulkhead {
+	cfg := defaultConfig()
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &Bulkhead{
+		Name:            name,
+		slots:           make(chan struct{}, cfg.MaxConcurrent),
+		maxQueue:        cfg.MaxQueue,
+		maxWait:         cfg.MaxWait,
+		breaker:         cfg.Breaker,
+		countRejections: cfg.CountRejections,
+	}
+}
+
+// Execute runs the given function through the bulkhead.
+func (h *Bulkhead) Execute(fn func() error) error {
+	return h.ExecuteContext(context.Background(), fn)
+}
+
+// ExecuteContext runs the given function through the bulkhead, waiting for a
+// slot no longer than ctx allows.
+func (h *Bulkhead) ExecuteContext(ctx context.Context, fn func() error) error {
+	if fn == nil {
+		return ErrNilFunction
+	}
+	return h.ExecuteCtx(ctx, func(context.Context) error {
+		return fn()
+	})
+}
+
+// ExecuteCtx runs the given function through the bulkhead and, if set, the
+// breaker, and passes it the context it should observe.
+func (h *Bulkhead) ExecuteCtx(ctx context.Context, fn func(context.Context) error) error {
+	if fn == nil {
+		return ErrNilFunction
+	}
+
+	if err := h.acquire(ctx); err != nil {
+		if h.breaker != nil && h.countRejections && stderrors.Is(err, ErrBulkheadFull) {
+			return stderrors.Join(err, h.recordRejection(ctx))
+		}
+		return err
+	}
+	defer h.release()
+
+	if h.breaker == nil {
+		return fn(ctx)
+	}
+	return h.breaker.ExecuteCtx(ctx, fn)
+}
+
+// InFlight returns the number of calls holding a slot.
+func (h *Bulkhead) InFlight() int64 {
+	return int64(len(h.slots))
+}
+
+// Waiting returns the number of calls queued for a slot.
+func (h *Bulkhead) Waiting() int64 {
+	return h.waiting.Load()
+}
+
+// acquire takes a slot, queueing for one if the queue has room.
+func (h *Bulkhead) acquire(ctx context.Context) error {
+	select {
+	case h.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if h.waiting.Add(1) > h.maxQueue {
+		h.waiting.Add(-1)
+		return ErrBulkheadFull
+	}
+	defer h.waiting.Add(-1)
+
+	var expired <-chan time.Time
+	if h.maxWait > 0 {
+		timer := time.NewTimer(h.maxWait)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case h.slots <- struct{}{}:
+		return nil
+	case <-expired:
+		return ErrBulkheadFull
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *Bulkhead) release() {
+	<-h.slots
+}
+
+// recordRejection counts a rejected call as a breaker failure. A breaker that
+// is already open has nothing to record.
+func (h *Bulkhead) recordRejection(ctx context.Context) error {
+	permit, err := h.breaker.Allow(ctx)
+	if err != nil {
+		if stderrors.Is(err, breaker.ErrCircuitOpen) {
+			return nil
+		}
+		return err
+	}
+	return permit.Failure(ErrBulkheadFull)
+}
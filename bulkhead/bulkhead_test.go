@@ -0,0 +1,186 @@
+package bulkhead
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	breaker "github.com/shuklasaharsh/circuitbreaker"
+)
+
+// hold occupies a slot of h until the returned release is called.
+func hold(t *testing.T, h *Bulkhead) (release func()) {
+	t.Helper()
+	started := make(chan struct{})
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		_ = h.Execute(func() error {
+			close(started)
+			<-done
+			return nil
+		})
+	}()
+	<-started
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+func TestExecuteNilFunction(t *testing.T) {
+	h := New("svc")
+	if err := h.Execute(nil); !errors.Is(err, ErrNilFunction) {
+		t.Fatalf("expected ErrNilFunction, got %v", err)
+	}
+	if err := h.ExecuteCtx(context.Background(), nil); !errors.Is(err, ErrNilFunction) {
+		t.Fatalf("expected ErrNilFunction, got %v", err)
+	}
+}
+
+func TestExecuteRejectsWhenFull(t *testing.T) {
+	h := New("svc", WithMaxConcurrent(1))
+	release := hold(t, h)
+	defer release()
+
+	if h.InFlight() != 1 {
+		t.Fatalf("expected 1 call in flight, got %d", h.InFlight())
+	}
+	called := false
+	err := h.Execute(func() error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, ErrBulkheadFull) {
+		t.Fatalf("expected ErrBulkheadFull, got %v", err)
+	}
+	if called {
+		t.Fatalf("rejected call should not run")
+	}
+}
+
+func TestExecuteReleasesSlot(t *testing.T) {
+	h := New("svc", WithMaxConcurrent(1))
+	boom := errors.New("boom")
+	if err := h.Execute(func() error { return boom }); !errors.Is(err, boom) {
+		t.Fatalf("expected call error, got %v", err)
+	}
+	if err := h.Execute(func() error { return nil }); err != nil {
+		t.Fatalf("expected slot to be released, got %v", err)
+	}
+	if h.InFlight() != 0 {
+		t.Fatalf("expected no calls in flight, got %d", h.InFlight())
+	}
+}
+
+func TestQueuedCallGetsSlot(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := New("svc", WithMaxConcurrent(1), WithMaxQueue(1))
+		release := hold(t, h)
+
+		result := make(chan error, 1)
+		go func() {
+			result <- h.Execute(func() error { return nil })
+		}()
+		synctest.Wait()
+		if h.Waiting() != 1 {
+			t.Fatalf("expected 1 waiting call, got %d", h.Waiting())
+		}
+		if err := h.Execute(func() error { return nil }); !errors.Is(err, ErrBulkheadFull) {
+			t.Fatalf("expected ErrBulkheadFull with a full queue, got %v", err)
+		}
+
+		release()
+		if err := <-result; err != nil {
+			t.Fatalf("expected queued call to run, got %v", err)
+		}
+	})
+}
+
+func TestQueuedCallMaxWait(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := New("svc", WithMaxConcurrent(1), WithMaxQueue(1), WithMaxWait(time.Second))
+		release := hold(t, h)
+		defer release()
+
+		start := time.Now()
+		if err := h.Execute(func() error { return nil }); !errors.Is(err, ErrBulkheadFull) {
+			t.Fatalf("expected ErrBulkheadFull after max wait, got %v", err)
+		}
+		if waited := time.Since(start); waited != time.Second {
+			t.Fatalf("expected to wait 1s, waited %v", waited)
+		}
+	})
+}
+
+func TestQueuedCallHonoursContext(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := New("svc", WithMaxConcurrent(1), WithMaxQueue(1))
+		release := hold(t, h)
+		defer release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := h.ExecuteContext(ctx, func() error { return nil }); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected DeadlineExceeded, got %v", err)
+		}
+	})
+}
+
+func TestExecuteThroughBreaker(t *testing.T) {
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithTimeout(time.Minute),
+	)
+	h := New("svc", WithBreaker(cb))
+
+	_ = h.Execute(func() error { return errors.New("boom") })
+	if err := h.Execute(func() error { return nil }); !errors.Is(err, breaker.ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if h.InFlight() != 0 {
+		t.Fatalf("expected rejected call to release its slot, got %d in flight", h.InFlight())
+	}
+}
+
+func TestRejectionsAsFailuresTripBreaker(t *testing.T) {
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithTimeout(time.Minute),
+	)
+	h := New("svc", WithMaxConcurrent(1), WithBreaker(cb), WithRejectionsAsFailures())
+	release := hold(t, h)
+
+	if err := h.Execute(func() error { return nil }); !errors.Is(err, ErrBulkheadFull) {
+		t.Fatalf("expected ErrBulkheadFull, got %v", err)
+	}
+	release()
+
+	state, err := cb.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != breaker.StateOpen {
+		t.Fatalf("expected rejection to trip the breaker, got %v", state)
+	}
+}
+
+func TestRejectionsNotCountedByDefault(t *testing.T) {
+	cb := breaker.New("svc", breaker.WithFailureThreshold(1))
+	h := New("svc", WithMaxConcurrent(1), WithBreaker(cb))
+	release := hold(t, h)
+
+	_ = h.Execute(func() error { return nil })
+	release()
+
+	record, err := cb.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if record.State != breaker.StateClosed || record.Failures != 0 {
+		t.Fatalf("expected untouched breaker, got %v with %d failures", record.State, record.Failures)
+	}
+}
@@ -0,0 +1,73 @@
+package bulkhead
+
+import (
+	"time"
+
+	breaker "github.com/shuklasaharsh/circuitbreaker"
+)
+
+type Config struct {
+	MaxConcurrent   int64
+	MaxQueue        int64
+	MaxWait         time.Duration
+	Breaker         *breaker.Breaker
+	CountRejections bool
+}
+
+type Option func(*Config)
+
+// WithMaxConcurrent limits the calls running at once.
+func WithMaxConcurrent(n int64) Option {
+	if n <= 0 {
+		panic(ErrInvalidMaxConcurrent)
+	}
+	return func(c *Config) {
+		c.MaxConcurrent = n
+	}
+}
+
+// WithMaxQueue lets up to n calls wait for a slot instead of being rejected
+// at once.
+func WithMaxQueue(n int64) Option {
+	if n < 0 {
+		panic(ErrInvalidQueueSize)
+	}
+	return func(c *Config) {
+		c.MaxQueue = n
+	}
+}
+
+// WithMaxWait bounds how long a queued call waits for a slot. Zero waits until
+// the call's context is done.
+func WithMaxWait(d time.Duration) Option {
+	if d < 0 {
+		panic(ErrInvalidDuration)
+	}
+	return func(c *Config) {
+		c.MaxWait = d
+	}
+}
+
+// WithBreaker runs calls that get a slot through b, so a call has to pass both.
+func WithBreaker(b *breaker.Breaker) Option {
+	if b == nil {
+		panic(ErrInvalidBreaker)
+	}
+	return func(c *Config) {
+		c.Breaker = b
+	}
+}
+
+// WithRejectionsAsFailures records ErrBulkheadFull rejections as failures of
+// the breaker set with WithBreaker, so a saturated dependency can trip it.
+func WithRejectionsAsFailures() Option {
+	return func(c *Config) {
+		c.CountRejections = true
+	}
+}
+
+func defaultConfig() Config {
+	return Config{
+		MaxConcurrent: 10,
+	}
+}
@@ -0,0 +1,49 @@
+package bulkhead
+
+import (
+	"testing"
+	"time"
+
+	breaker "github.com/shuklasaharsh/circuitbreaker"
+)
+
+func assertPanics(t *testing.T, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic")
+		}
+	}()
+	fn()
+}
+
+func TestDefaultConfig(t *testing.T) {
+	cfg := defaultConfig()
+	if cfg.MaxConcurrent != 10 || cfg.MaxQueue != 0 || cfg.MaxWait != 0 || cfg.Breaker != nil || cfg.CountRejections {
+		t.Fatalf("unexpected defaults: %+v", cfg)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	cfg := defaultConfig()
+	cb := breaker.New("svc")
+	for _, opt := range []Option{
+		WithMaxConcurrent(3),
+		WithMaxQueue(5),
+		WithMaxWait(time.Second),
+		WithBreaker(cb),
+		WithRejectionsAsFailures(),
+	} {
+		opt(&cfg)
+	}
+	if cfg.MaxConcurrent != 3 || cfg.MaxQueue != 5 || cfg.MaxWait != time.Second || cfg.Breaker != cb || !cfg.CountRejections {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+}
+
+func TestOptionsPanic(t *testing.T) {
+	assertPanics(t, func() { _ = WithMaxConcurrent(0) })
+	assertPanics(t, func() { _ = WithMaxQueue(-1) })
+	assertPanics(t, func() { _ = WithMaxWait(-time.Second) })
+	assertPanics(t, func() { _ = WithBreaker(nil) })
+}
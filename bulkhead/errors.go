@@ -0,0 +1,17 @@
+package bulkhead
+
+import "github.com/shuklasaharsh/circuitbreaker/errors"
+
+var (
+	ErrInvalidMaxConcurrent = errors.NewError(100, "supplied max concurrent calls is invalid", errors.ConfigError)
+	ErrInvalidQueueSize     = errors.NewError(101, "supplied queue size is invalid", errors.ConfigError)
+	ErrInvalidDuration      = errors.NewError(102, "supplied max wait duration is invalid", errors.ConfigError)
+	ErrInvalidBreaker       = errors.NewError(103, "breaker cannot be nil", errors.ConfigError)
+	ErrNilFunction          = errors.NewError(104, "function cannot be nil", errors.ConfigError)
+)
+
+var (
+	// ErrBulkheadFull rejects a call that found every slot taken and could not
+	// wait: the queue was full, or the max wait elapsed first.
+	ErrBulkheadFull = errors.NewError(100, "bulkhead is full", errors.BulkheadError)
+)
@@ -0,0 +1,39 @@
+package bulkhead
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	breaker "github.com/shuklasaharsh/circuitbreaker"
+)
+
+func TestErrorMessages(t *testing.T) {
+	cases := []struct {
+		name    string
+		err     error
+		substrs []string
+	}{
+		{"invalid-max-concurrent", ErrInvalidMaxConcurrent, []string{"max concurrent", "code"}},
+		{"invalid-queue-size", ErrInvalidQueueSize, []string{"queue size", "code"}},
+		{"invalid-duration", ErrInvalidDuration, []string{"max wait duration", "code"}},
+		{"invalid-breaker", ErrInvalidBreaker, []string{"breaker", "code"}},
+		{"nil-function", ErrNilFunction, []string{"function", "code"}},
+		{"bulkhead-full", ErrBulkheadFull, []string{"bulkhead error", "bulkhead is full"}},
+	}
+
+	for _, tc := range cases {
+		msg := tc.err.Error()
+		for _, substr := range tc.substrs {
+			if !strings.Contains(msg, substr) {
+				t.Fatalf("%s: expected %q in %q", tc.name, substr, msg)
+			}
+		}
+	}
+}
+
+func TestErrorsDistinctFromBreaker(t *testing.T) {
+	if errors.Is(ErrInvalidDuration, breaker.ErrInvalidDuration) {
+		t.Fatalf("expected bulkhead and breaker errors to be distinct")
+	}
+}
@@ -0,0 +1,78 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+)
+
+// Outcome is how the breaker records the result of a call.
+type Outcome uint8
+
+const (
+	// OutcomeSuccess records the call as a success.
+	OutcomeSuccess Outcome = iota
+	// OutcomeFailure records the call as a failure.
+	OutcomeFailure
+	// OutcomeIgnore leaves the breaker counters untouched.
+	OutcomeIgnore
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeSuccess:
+		return "Success"
+	case OutcomeFailure:
+		return "Failure"
+	case OutcomeIgnore:
+		return "Ignore"
+	default:
+		return "Unknown"
+	}
+}
+
+// classify maps the error returned by a call to the outcome to record. Nil
+// errors are successes; ignored errors are checked before the classifier, and
+// every other error is a failure unless the classifier says otherwise.
+func (b *Breaker) classify(err error) Outcome {
+	if err == nil {
+		return OutcomeSuccess
+	}
+	for _, ignored := range b.ignoredErrors {
+		if ignored(err) {
+			return OutcomeIgnore
+		}
+	}
+	if b.classifier != nil {
+		return b.classifier(err)
+	}
+	return OutcomeFailure
+}
+
+// outcome classifies a failed call with its contexts in mind. A call abandoned
+// because the caller cancelled ctx is ignored, and a call that ran past the
+// deadline of callCtx is a failure; anything else goes through classify.
+func (b *Breaker) outcome(ctx, callCtx context.Context, err error) Outcome {
+	if err == nil {
+		return OutcomeSuccess
+	}
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return OutcomeIgnore
+	}
+	if errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return OutcomeFailure
+	}
+	return b.classify(err)
+}
+
+func matchErrorIs(target error) func(error) bool {
+	return func(err error) bool {
+		return errors.Is(err, target)
+	}
+}
+
+func matchErrorAs[E error]() func(error) bool {
+	return func(err error) bool {
+		var target E
+		return errors.As(err, &target)
+	}
+}
@@ -0,0 +1,141 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type validationError struct {
+	field string
+}
+
+func (e *validationError) Error() string {
+	return "invalid " + e.field
+}
+
+func TestClassifyDefaults(t *testing.T) {
+	b := New("svc")
+	if got := b.classify(nil); got != OutcomeSuccess {
+		t.Fatalf("expected success for nil error, got %v", got)
+	}
+	if got := b.classify(errors.New("boom")); got != OutcomeFailure {
+		t.Fatalf("expected failure, got %v", got)
+	}
+}
+
+func TestClassifyIgnoredErrors(t *testing.T) {
+	b := New("svc",
+		WithIgnoredErrors(context.Canceled),
+		WithIgnoredErrorType[*validationError](),
+	)
+	cases := []struct {
+		name     string
+		err      error
+		expected Outcome
+	}{
+		{"is", fmt.Errorf("wrapped: %w", context.Canceled), OutcomeIgnore},
+		{"as", fmt.Errorf("wrapped: %w", &validationError{field: "name"}), OutcomeIgnore},
+		{"other", errors.New("boom"), OutcomeFailure},
+	}
+	for _, tc := range cases {
+		if got := b.classify(tc.err); got != tc.expected {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestClassifyIgnoredBeforeClassifier(t *testing.T) {
+	b := New("svc",
+		WithIgnoredErrors(context.Canceled),
+		WithErrorClassifier(func(error) Outcome { return OutcomeSuccess }),
+	)
+	if got := b.classify(context.Canceled); got != OutcomeIgnore {
+		t.Fatalf("expected ignore, got %v", got)
+	}
+	if got := b.classify(errors.New("boom")); got != OutcomeSuccess {
+		t.Fatalf("expected classifier outcome, got %v", got)
+	}
+}
+
+func TestOutcomeString(t *testing.T) {
+	cases := []struct {
+		outcome  Outcome
+		expected string
+	}{
+		{OutcomeSuccess, "Success"},
+		{OutcomeFailure, "Failure"},
+		{OutcomeIgnore, "Ignore"},
+		{Outcome(99), "Unknown"},
+	}
+	for _, tc := range cases {
+		if tc.outcome.String() != tc.expected {
+			t.Fatalf("expected %q, got %q", tc.expected, tc.outcome.String())
+		}
+	}
+}
+
+func TestExecuteIgnoredErrorLeavesCounters(t *testing.T) {
+	b := New("svc", WithFailureThreshold(1), WithIgnoredErrors(context.Canceled))
+	err := b.Execute(func() error { return context.Canceled })
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected call error returned, got %v", err)
+	}
+
+	record, err := b.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if record.State != StateClosed || record.Failures != 0 {
+		t.Fatalf("expected untouched closed record, got %v with %d failures", record.State, record.Failures)
+	}
+}
+
+func TestExecuteClassifiedSuccessReturnsError(t *testing.T) {
+	notFound := errors.New("not found")
+	b := New("svc",
+		WithFailureThreshold(1),
+		WithErrorClassifier(func(err error) Outcome {
+			if errors.Is(err, notFound) {
+				return OutcomeSuccess
+			}
+			return OutcomeFailure
+		}),
+	)
+	if err := b.Execute(func() error { return notFound }); !errors.Is(err, notFound) {
+		t.Fatalf("expected call error returned, got %v", err)
+	}
+	state, err := b.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != StateClosed {
+		t.Fatalf("expected closed, got %v", state)
+	}
+}
+
+func TestExecuteIgnoredErrorReleasesHalfOpenPermit(t *testing.T) {
+	timeout := 5 * time.Millisecond
+	b := New("svc",
+		WithFailureThreshold(1),
+		WithTimeout(timeout),
+		WithHalfOpenMaxCalls(1),
+		WithIgnoredErrors(context.Canceled),
+	)
+	_ = b.Execute(func() error { return errors.New("boom") })
+	time.Sleep(timeout + time.Millisecond)
+
+	_ = b.Execute(func() error { return context.Canceled })
+	record, err := b.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if record.State != StateHalfOpen || record.HalfOpenCalls != 0 || record.Successes != 0 {
+		t.Fatalf("expected released half-open permit, got %#v", record)
+	}
+	if err := b.Execute(func() error { return nil }); err != nil {
+		t.Fatalf("expected next probe admitted, got %v", err)
+	}
+}
@@ -1,16 +1,47 @@
 package breaker
 
 import (
+	"context"
+	"log/slog"
 	"time"
 
 	"github.com/shuklasaharsh/circuitbreaker/storage"
 )
 
 type Config struct {
-	FailureThreshold int64
-	SuccessThreshold int64
-	Timeout          time.Duration
-	Store            storage.Store
+	FailureThreshold      int64
+	SuccessThreshold      int64
+	Timeout               time.Duration
+	Store                 storage.Store
+	SlidingWindowSize     int64
+	TimeWindow            time.Duration
+	TimeWindowBuckets     int64
+	FailureRateThreshold  float64
+	MinimumCalls          int64
+	SlowCallDuration      time.Duration
+	SlowCallRateThreshold float64
+	HalfOpenMaxCalls      int64
+	OpenBackoffInitial    time.Duration
+	OpenBackoffMax        time.Duration
+	OpenBackoffMultiplier float64
+	OpenJitter            time.Duration
+	ErrorClassifier       func(error) Outcome
+	IgnoredErrors         []func(error) bool
+	PanicPolicy           PanicPolicy
+	CallTimeout           time.Duration
+	OnStateChange         func(name string, from, to State, record storage.Record)
+	Fallback              func(context.Context, error) error
+	Clock                 Clock
+	HealthCheck           HealthChecker
+	HealthCheckInterval   time.Duration
+	HealthCheckTransition State
+	Logger                *slog.Logger
+	LogLevels             LogLevels
+	RejectionLogInterval  time.Duration
+	ThrottleK             float64
+	ThrottleWindow        time.Duration
+	RecoveryRamp          time.Duration
+	RecoveryRampCurve     RampCurve
 }
 
 type Option func(*Config)
@@ -43,6 +74,318 @@ func WithTimeout(d time.Duration) Option {
 	}
 }
 
+// WithSlidingWindow trips on the failure rate of the last size calls instead of
+// on consecutive failures.
+func WithSlidingWindow(size int64) Option {
+	if size <= 0 {
+		panic(ErrInvalidWindowSize)
+	}
+	return func(c *Config) {
+		c.SlidingWindowSize = size
+		c.TimeWindow = 0
+		c.TimeWindowBuckets = 0
+	}
+}
+
+// WithTimeWindow trips on the failure rate of the calls made during the last d,
+// aggregated into the given number of buckets.
+func WithTimeWindow(d time.Duration, buckets int64) Option {
+	if d <= 0 {
+		panic(ErrInvalidDuration)
+	}
+	if buckets <= 0 || d/time.Duration(buckets) <= 0 {
+		panic(ErrInvalidWindowSize)
+	}
+	return func(c *Config) {
+		c.TimeWindow = d
+		c.TimeWindowBuckets = buckets
+		c.SlidingWindowSize = 0
+	}
+}
+
+// WithFailureRateThreshold sets the failure percentage, in (0, 100], at which a
+// windowed breaker trips.
+func WithFailureRateThreshold(pct float64) Option {
+	if pct <= 0 || pct > 100 {
+		panic(ErrInvalidThresholdValue)
+	}
+	return func(c *Config) {
+		c.FailureRateThreshold = pct
+	}
+}
+
+// WithMinimumCalls sets how many calls a window must hold before its failure
+// rate is evaluated. It is capped at the size of a count-based window.
+func WithMinimumCalls(n int64) Option {
+	if n <= 0 {
+		panic(ErrInvalidThresholdValue)
+	}
+	return func(c *Config) {
+		c.MinimumCalls = n
+	}
+}
+
+// WithSlowCallDuration counts calls that take longer than d as slow, even when
+// they succeed. Slow calls are tracked in the sliding window; when none is
+// configured they are kept in a count-based window of the last 100 calls,
+// while failures keep tripping on WithFailureThreshold.
+func WithSlowCallDuration(d time.Duration) Option {
+	if d <= 0 {
+		panic(ErrInvalidDuration)
+	}
+	return func(c *Config) {
+		c.SlowCallDuration = d
+	}
+}
+
+// WithSlowCallRateThreshold sets the slow call percentage, in (0, 100], at
+// which the breaker trips.
+func WithSlowCallRateThreshold(pct float64) Option {
+	if pct <= 0 || pct > 100 {
+		panic(ErrInvalidThresholdValue)
+	}
+	return func(c *Config) {
+		c.SlowCallRateThreshold = pct
+	}
+}
+
+// WithHalfOpenMaxCalls limits how many trial calls may be in flight while the
+// breaker is half-open. Calls beyond the limit are rejected with ErrCircuitOpen.
+func WithHalfOpenMaxCalls(n int64) Option {
+	if n <= 0 {
+		panic(ErrInvalidThresholdValue)
+	}
+	return func(c *Config) {
+		c.HalfOpenMaxCalls = n
+	}
+}
+
+// WithOpenBackoff replaces the fixed timeout with an open duration that starts
+// at initial and is multiplied on every consecutive trip, up to max. A close
+// resets it to initial.
+func WithOpenBackoff(initial, max time.Duration, multiplier float64) Option {
+	if initial <= 0 || max < initial {
+		panic(ErrInvalidDuration)
+	}
+	if multiplier < 1 {
+		panic(ErrInvalidMultiplier)
+	}
+	return func(c *Config) {
+		c.OpenBackoffInitial = initial
+		c.OpenBackoffMax = max
+		c.OpenBackoffMultiplier = multiplier
+	}
+}
+
+// WithOpenJitter extends every open period by a random duration in [0, d).
+// The resulting deadline is stored in the record, so breakers sharing a store
+// move to half-open together instead of each computing its own deadline.
+func WithOpenJitter(d time.Duration) Option {
+	if d <= 0 {
+		panic(ErrInvalidDuration)
+	}
+	return func(c *Config) {
+		c.OpenJitter = d
+	}
+}
+
+// WithErrorClassifier decides how non-nil errors returned by calls are
+// recorded. Errors matched by WithIgnoredErrors are ignored before it is
+// consulted.
+func WithErrorClassifier(fn func(error) Outcome) Option {
+	if fn == nil {
+		panic(ErrNilFunction)
+	}
+	return func(c *Config) {
+		c.ErrorClassifier = fn
+	}
+}
+
+// WithIgnoredErrors ignores call errors that match any of errs via errors.Is.
+func WithIgnoredErrors(errs ...error) Option {
+	return func(c *Config) {
+		for _, err := range errs {
+			if err != nil {
+				c.IgnoredErrors = append(c.IgnoredErrors, matchErrorIs(err))
+			}
+		}
+	}
+}
+
+// WithIgnoredErrorType ignores call errors that contain an E via errors.As.
+func WithIgnoredErrorType[E error]() Option {
+	return func(c *Config) {
+		c.IgnoredErrors = append(c.IgnoredErrors, matchErrorAs[E]())
+	}
+}
+
+// WithPanicPolicy decides whether a recorded panic is re-raised or returned
+// as a *PanicError.
+func WithPanicPolicy(policy PanicPolicy) Option {
+	if policy != PanicRepanic && policy != PanicReturnError {
+		panic(ErrInvalidPanicPolicy)
+	}
+	return func(c *Config) {
+		c.PanicPolicy = policy
+	}
+}
+
+// WithCallTimeout gives every call a context that expires after d. Calls that
+// fail once it has expired are recorded as failures.
+func WithCallTimeout(d time.Duration) Option {
+	if d <= 0 {
+		panic(ErrInvalidDuration)
+	}
+	return func(c *Config) {
+		c.CallTimeout = d
+	}
+}
+
+// WithOnStateChange calls fn after every committed state transition, outside
+// of any store lock.
+func WithOnStateChange(fn func(name string, from, to State, record storage.Record)) Option {
+	if fn == nil {
+		panic(ErrNilFunction)
+	}
+	return func(c *Config) {
+		c.OnStateChange = fn
+	}
+}
+
+// WithFallback calls fn when Execute, ExecuteContext or ExecuteCtx is rejected
+// with ErrCircuitOpen or the call fails. Its result is returned in place of
+// the original error. Run is not affected; use RunWithFallback instead.
+func WithFallback(fn func(ctx context.Context, err error) error) Option {
+	if fn == nil {
+		panic(ErrNilFunction)
+	}
+	return func(c *Config) {
+		c.Fallback = fn
+	}
+}
+
+// Clock tells the breaker the time. storage.SystemClock is the default.
+type Clock = storage.Clock
+
+// WithClock reads the time from clock instead of the wall clock, so tests can
+// move through open periods and windows without sleeping.
+func WithClock(clock Clock) Option {
+	if clock == nil {
+		panic(ErrInvalidClock)
+	}
+	return func(c *Config) {
+		c.Clock = clock
+	}
+}
+
+// WithHealthCheck probes hc in the background while the breaker is open and
+// leaves the open state as soon as a check passes, instead of waiting for the
+// timeout and spending user calls on trials. Checks are scheduled on a real
+// ticker rather than the breaker's clock, so tests drive them with
+// testing/synctest instead of a FakeClock.
+func WithHealthCheck(hc HealthChecker) Option {
+	if hc == nil {
+		panic(ErrInvalidHealthCheck)
+	}
+	return func(c *Config) {
+		c.HealthCheck = hc
+	}
+}
+
+// WithHealthCheckInterval sets how often the health check runs while open.
+// Each check is also bounded by the interval.
+func WithHealthCheckInterval(d time.Duration) Option {
+	if d <= 0 {
+		panic(ErrInvalidDuration)
+	}
+	return func(c *Config) {
+		c.HealthCheckInterval = d
+	}
+}
+
+// WithHealthCheckTransition sets the state a passing health check moves the
+// breaker to: StateHalfOpen, the default, or StateClosed.
+func WithHealthCheckTransition(to State) Option {
+	if to != StateHalfOpen && to != StateClosed {
+		panic(ErrInvalidTransition)
+	}
+	return func(c *Config) {
+		c.HealthCheckTransition = to
+	}
+}
+
+// WithLogger logs state changes, rejected calls and store update failures to
+// logger, with the breaker name and its counters as attributes.
+func WithLogger(logger *slog.Logger) Option {
+	if logger == nil {
+		panic(ErrInvalidLogger)
+	}
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+// WithLogLevels sets the level of each kind of log record.
+func WithLogLevels(levels LogLevels) Option {
+	return func(c *Config) {
+		c.LogLevels = levels
+	}
+}
+
+// WithRejectionLogInterval logs at most one rejected call per d, counting the
+// rest in the next record's "suppressed" attribute. Zero logs every rejection.
+func WithRejectionLogInterval(d time.Duration) Option {
+	if d < 0 {
+		panic(ErrInvalidDuration)
+	}
+	return func(c *Config) {
+		c.RejectionLogInterval = d
+	}
+}
+
+// WithAdaptiveThrottling replaces the open/closed state machine with
+// client-side throttling: the breaker stays closed and rejects each call with
+// ErrCircuitOpen with probability max(0, (requests - k*accepts)/(requests+1)),
+// counted over the last window. A k of 2 is a common choice; lower values
+// throttle sooner. It takes precedence over WithSlidingWindow and
+// WithTimeWindow.
+func WithAdaptiveThrottling(k float64, window time.Duration) Option {
+	if k < 1 {
+		panic(ErrInvalidMultiplier)
+	}
+	if window/throttleBuckets <= 0 {
+		panic(ErrInvalidDuration)
+	}
+	return func(c *Config) {
+		c.ThrottleK = k
+		c.ThrottleWindow = window
+	}
+}
+
+// WithRecoveryRamp lets traffic back in gradually for d after the breaker
+// closes from half-open or a passing health check, rejecting the calls it
+// holds back with ErrCircuitOpen. The ramp start is stored in the record, so
+// breakers sharing a store follow the same curve.
+func WithRecoveryRamp(d time.Duration) Option {
+	if d <= 0 {
+		panic(ErrInvalidDuration)
+	}
+	return func(c *Config) {
+		c.RecoveryRamp = d
+	}
+}
+
+// WithRecoveryRampCurve sets the shape of the recovery ramp: RampLinear, the
+// default, or RampExponential.
+func WithRecoveryRampCurve(curve RampCurve) Option {
+	if curve != RampLinear && curve != RampExponential {
+		panic(ErrInvalidRampCurve)
+	}
+	return func(c *Config) {
+		c.RecoveryRampCurve = curve
+	}
+}
+
 func WithStorage(store storage.Store) Option {
 	if store == nil {
 		panic(ErrInvalidStorage)
@@ -54,9 +397,17 @@ func WithStorage(store storage.Store) Option {
 
 func defaultConfig() Config {
 	return Config{
-		FailureThreshold: 5,
-		SuccessThreshold: 2,
-		Timeout:          60 * time.Second,
-		Store:            storage.NewMemoryStore(),
+		FailureThreshold:      5,
+		SuccessThreshold:      2,
+		Timeout:               60 * time.Second,
+		Store:                 storage.NewMemoryStore(),
+		FailureRateThreshold:  50,
+		MinimumCalls:          10,
+		SlowCallRateThreshold: 100,
+		Clock:                 storage.SystemClock{},
+		HealthCheckInterval:   defaultHealthCheckInterval,
+		HealthCheckTransition: StateHalfOpen,
+		LogLevels:             defaultLogLevels(),
+		RejectionLogInterval:  defaultRejectionLogInterval,
 	}
 }
@@ -1,9 +1,12 @@
 package breaker
 
 import (
+	"context"
+	"log/slog"
 	"testing"
 	"time"
 
+	"github.com/shuklasaharsh/circuitbreaker/breakertest"
 	"github.com/shuklasaharsh/circuitbreaker/storage"
 )
 
@@ -81,3 +84,277 @@ func assertPanics(t *testing.T, fn func()) {
 	}()
 	fn()
 }
+
+func TestWithSlidingWindow(t *testing.T) {
+	cfg := defaultConfig()
+	WithSlidingWindow(50)(&cfg)
+	if cfg.SlidingWindowSize != 50 {
+		t.Fatalf("expected window size 50, got %d", cfg.SlidingWindowSize)
+	}
+}
+
+func TestWithSlidingWindowPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithSlidingWindow(0) })
+}
+
+func TestWithFailureRateThreshold(t *testing.T) {
+	cfg := defaultConfig()
+	WithFailureRateThreshold(25)(&cfg)
+	if cfg.FailureRateThreshold != 25 {
+		t.Fatalf("expected failure rate threshold 25, got %v", cfg.FailureRateThreshold)
+	}
+}
+
+func TestWithFailureRateThresholdPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithFailureRateThreshold(0) })
+	assertPanics(t, func() { _ = WithFailureRateThreshold(101) })
+}
+
+func TestWithMinimumCalls(t *testing.T) {
+	cfg := defaultConfig()
+	WithMinimumCalls(7)(&cfg)
+	if cfg.MinimumCalls != 7 {
+		t.Fatalf("expected minimum calls 7, got %d", cfg.MinimumCalls)
+	}
+}
+
+func TestWithMinimumCallsPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithMinimumCalls(0) })
+}
+
+func TestWithTimeWindow(t *testing.T) {
+	cfg := defaultConfig()
+	WithSlidingWindow(10)(&cfg)
+	WithTimeWindow(30*time.Second, 30)(&cfg)
+	if cfg.TimeWindow != 30*time.Second || cfg.TimeWindowBuckets != 30 {
+		t.Fatalf("expected 30s window with 30 buckets, got %v/%d", cfg.TimeWindow, cfg.TimeWindowBuckets)
+	}
+	if cfg.SlidingWindowSize != 0 {
+		t.Fatalf("expected count window cleared, got %d", cfg.SlidingWindowSize)
+	}
+}
+
+func TestWithTimeWindowPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithTimeWindow(0, 10) })
+	assertPanics(t, func() { _ = WithTimeWindow(time.Second, 0) })
+	assertPanics(t, func() { _ = WithTimeWindow(time.Nanosecond, 2) })
+}
+
+func TestWithSlowCallDuration(t *testing.T) {
+	cfg := defaultConfig()
+	WithSlowCallDuration(2 * time.Second)(&cfg)
+	if cfg.SlowCallDuration != 2*time.Second {
+		t.Fatalf("expected slow call duration 2s, got %v", cfg.SlowCallDuration)
+	}
+}
+
+func TestWithSlowCallDurationPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithSlowCallDuration(0) })
+}
+
+func TestWithSlowCallRateThreshold(t *testing.T) {
+	cfg := defaultConfig()
+	WithSlowCallRateThreshold(80)(&cfg)
+	if cfg.SlowCallRateThreshold != 80 {
+		t.Fatalf("expected slow call rate threshold 80, got %v", cfg.SlowCallRateThreshold)
+	}
+}
+
+func TestWithSlowCallRateThresholdPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithSlowCallRateThreshold(-1) })
+	assertPanics(t, func() { _ = WithSlowCallRateThreshold(150) })
+}
+
+func TestWithHalfOpenMaxCalls(t *testing.T) {
+	cfg := defaultConfig()
+	WithHalfOpenMaxCalls(3)(&cfg)
+	if cfg.HalfOpenMaxCalls != 3 {
+		t.Fatalf("expected half-open max calls 3, got %d", cfg.HalfOpenMaxCalls)
+	}
+}
+
+func TestWithHalfOpenMaxCallsPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithHalfOpenMaxCalls(0) })
+}
+
+func TestWithOpenBackoff(t *testing.T) {
+	cfg := defaultConfig()
+	WithOpenBackoff(time.Second, time.Minute, 2)(&cfg)
+	if cfg.OpenBackoffInitial != time.Second || cfg.OpenBackoffMax != time.Minute || cfg.OpenBackoffMultiplier != 2 {
+		t.Fatalf("unexpected backoff: %v %v %v", cfg.OpenBackoffInitial, cfg.OpenBackoffMax, cfg.OpenBackoffMultiplier)
+	}
+}
+
+func TestWithOpenBackoffPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithOpenBackoff(0, time.Minute, 2) })
+	assertPanics(t, func() { _ = WithOpenBackoff(time.Minute, time.Second, 2) })
+	assertPanics(t, func() { _ = WithOpenBackoff(time.Second, time.Minute, 0.5) })
+}
+
+func TestWithOpenJitter(t *testing.T) {
+	cfg := defaultConfig()
+	WithOpenJitter(3 * time.Second)(&cfg)
+	if cfg.OpenJitter != 3*time.Second {
+		t.Fatalf("expected jitter 3s, got %v", cfg.OpenJitter)
+	}
+}
+
+func TestWithOpenJitterPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithOpenJitter(0) })
+}
+
+func TestWithErrorClassifier(t *testing.T) {
+	cfg := defaultConfig()
+	WithErrorClassifier(func(error) Outcome { return OutcomeIgnore })(&cfg)
+	if cfg.ErrorClassifier == nil || cfg.ErrorClassifier(nil) != OutcomeIgnore {
+		t.Fatalf("expected custom classifier")
+	}
+}
+
+func TestWithErrorClassifierPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithErrorClassifier(nil) })
+}
+
+func TestWithIgnoredErrorsAppends(t *testing.T) {
+	cfg := defaultConfig()
+	WithIgnoredErrors(ErrCircuitOpen, nil)(&cfg)
+	WithIgnoredErrorType[*validationError]()(&cfg)
+	if len(cfg.IgnoredErrors) != 2 {
+		t.Fatalf("expected 2 ignored error matchers, got %d", len(cfg.IgnoredErrors))
+	}
+}
+
+func TestWithPanicPolicy(t *testing.T) {
+	cfg := defaultConfig()
+	if cfg.PanicPolicy != PanicRepanic {
+		t.Fatalf("expected repanic by default, got %v", cfg.PanicPolicy)
+	}
+	WithPanicPolicy(PanicReturnError)(&cfg)
+	if cfg.PanicPolicy != PanicReturnError {
+		t.Fatalf("expected return error policy, got %v", cfg.PanicPolicy)
+	}
+}
+
+func TestWithPanicPolicyPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithPanicPolicy(PanicPolicy(9)) })
+}
+
+func TestWithCallTimeout(t *testing.T) {
+	cfg := defaultConfig()
+	WithCallTimeout(250 * time.Millisecond)(&cfg)
+	if cfg.CallTimeout != 250*time.Millisecond {
+		t.Fatalf("expected call timeout 250ms, got %v", cfg.CallTimeout)
+	}
+}
+
+func TestWithCallTimeoutPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithCallTimeout(0) })
+}
+
+func TestWithOnStateChange(t *testing.T) {
+	cfg := defaultConfig()
+	WithOnStateChange(func(string, State, State, storage.Record) {})(&cfg)
+	if cfg.OnStateChange == nil {
+		t.Fatalf("expected state change callback")
+	}
+}
+
+func TestWithOnStateChangePanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithOnStateChange(nil) })
+}
+
+func TestWithFallback(t *testing.T) {
+	cfg := defaultConfig()
+	WithFallback(func(context.Context, error) error { return nil })(&cfg)
+	if cfg.Fallback == nil {
+		t.Fatalf("expected fallback to be set")
+	}
+}
+
+func TestWithFallbackPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithFallback(nil) })
+}
+
+func TestWithClock(t *testing.T) {
+	cfg := defaultConfig()
+	if _, ok := cfg.Clock.(storage.SystemClock); !ok {
+		t.Fatalf("expected system clock by default, got %T", cfg.Clock)
+	}
+	clock := breakertest.NewFakeClock(time.Now())
+	WithClock(clock)(&cfg)
+	if cfg.Clock != clock {
+		t.Fatalf("expected fake clock to be set")
+	}
+}
+
+func TestWithClockPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithClock(nil) })
+}
+
+func TestWithHealthCheck(t *testing.T) {
+	cfg := defaultConfig()
+	if cfg.HealthCheckInterval != 5*time.Second || cfg.HealthCheckTransition != StateHalfOpen {
+		t.Fatalf("unexpected health check defaults: %v %v", cfg.HealthCheckInterval, cfg.HealthCheckTransition)
+	}
+	WithHealthCheck(HealthCheckerFunc(func(context.Context) error { return nil }))(&cfg)
+	WithHealthCheckInterval(time.Second)(&cfg)
+	WithHealthCheckTransition(StateClosed)(&cfg)
+	if cfg.HealthCheck == nil || cfg.HealthCheckInterval != time.Second || cfg.HealthCheckTransition != StateClosed {
+		t.Fatalf("expected health check options to be set")
+	}
+}
+
+func TestWithHealthCheckPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithHealthCheck(nil) })
+	assertPanics(t, func() { _ = WithHealthCheckInterval(0) })
+	assertPanics(t, func() { _ = WithHealthCheckTransition(StateOpen) })
+}
+
+func TestWithLogger(t *testing.T) {
+	cfg := defaultConfig()
+	if cfg.LogLevels.Rejection != slog.LevelWarn || cfg.RejectionLogInterval != time.Second {
+		t.Fatalf("unexpected logging defaults: %+v %v", cfg.LogLevels, cfg.RejectionLogInterval)
+	}
+	logger := slog.Default()
+	WithLogger(logger)(&cfg)
+	WithRejectionLogInterval(0)(&cfg)
+	if cfg.Logger != logger || cfg.RejectionLogInterval != 0 {
+		t.Fatalf("expected logging options to be set")
+	}
+}
+
+func TestWithLoggerPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithLogger(nil) })
+	assertPanics(t, func() { _ = WithRejectionLogInterval(-time.Second) })
+}
+
+func TestWithAdaptiveThrottling(t *testing.T) {
+	cfg := defaultConfig()
+	WithAdaptiveThrottling(2, time.Minute)(&cfg)
+	if cfg.ThrottleK != 2 || cfg.ThrottleWindow != time.Minute {
+		t.Fatalf("unexpected throttling: %v %v", cfg.ThrottleK, cfg.ThrottleWindow)
+	}
+}
+
+func TestWithAdaptiveThrottlingPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithAdaptiveThrottling(0.5, time.Minute) })
+	assertPanics(t, func() { _ = WithAdaptiveThrottling(2, 0) })
+	assertPanics(t, func() { _ = WithAdaptiveThrottling(2, time.Nanosecond) })
+}
+
+func TestWithRecoveryRamp(t *testing.T) {
+	cfg := defaultConfig()
+	if cfg.RecoveryRampCurve != RampLinear {
+		t.Fatalf("expected linear ramp by default, got %v", cfg.RecoveryRampCurve)
+	}
+	WithRecoveryRamp(time.Minute)(&cfg)
+	WithRecoveryRampCurve(RampExponential)(&cfg)
+	if cfg.RecoveryRamp != time.Minute || cfg.RecoveryRampCurve != RampExponential {
+		t.Fatalf("unexpected ramp: %v %v", cfg.RecoveryRamp, cfg.RecoveryRampCurve)
+	}
+}
+
+func TestWithRecoveryRampPanics(t *testing.T) {
+	assertPanics(t, func() { _ = WithRecoveryRamp(0) })
+	assertPanics(t, func() { _ = WithRecoveryRampCurve(RampCurve(9)) })
+}
@@ -7,8 +7,18 @@ var (
 	ErrInvalidDuration       = errors.NewError(101, "supplied duration is invalid", errors.ConfigError)
 	ErrNilFunction           = errors.NewError(102, "function cannot be nil", errors.ConfigError)
 	ErrInvalidStorage        = errors.NewError(103, "storage cannot be nil", errors.ConfigError)
+	ErrInvalidWindowSize     = errors.NewError(104, "supplied window size is invalid", errors.ConfigError)
+	ErrInvalidMultiplier     = errors.NewError(105, "supplied multiplier is invalid", errors.ConfigError)
+	ErrNilBreaker            = errors.NewError(106, "breaker cannot be nil", errors.ConfigError)
+	ErrInvalidPanicPolicy    = errors.NewError(107, "supplied panic policy is invalid", errors.ConfigError)
+	ErrInvalidClock          = errors.NewError(108, "clock cannot be nil", errors.ConfigError)
+	ErrInvalidHealthCheck    = errors.NewError(109, "health checker cannot be nil", errors.ConfigError)
+	ErrInvalidTransition     = errors.NewError(110, "supplied health check transition is invalid", errors.ConfigError)
+	ErrInvalidLogger         = errors.NewError(111, "logger cannot be nil", errors.ConfigError)
+	ErrInvalidRampCurve      = errors.NewError(112, "supplied ramp curve is invalid", errors.ConfigError)
 )
 
 var (
 	ErrCircuitOpen = errors.NewError(100, "circuit breaker is open", errors.CircuitStateError)
+	ErrUnhealthy   = errors.NewError(101, "health check failed", errors.CircuitStateError)
 )
@@ -24,3 +24,10 @@ func TestNewError(t *testing.T) {
 		t.Fatalf("unexpected error fields: %#v", cbErr)
 	}
 }
+
+func TestErrRetryBudgetExhausted(t *testing.T) {
+	expected := "retry error : code 100 : retry budget exhausted"
+	if ErrRetryBudgetExhausted.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, ErrRetryBudgetExhausted.Error())
+	}
+}
@@ -5,4 +5,6 @@ type ErrorFmt string
 const (
 	ConfigError       ErrorFmt = "configuration error"
 	CircuitStateError ErrorFmt = "circuit state error"
+	BulkheadError     ErrorFmt = "bulkhead error"
+	RetryError        ErrorFmt = "retry error"
 )
@@ -0,0 +1,7 @@
+package errors
+
+var (
+	// ErrRetryBudgetExhausted reports a retry that was skipped because the
+	// retry budget had no tokens left.
+	ErrRetryBudgetExhausted = NewError(100, "retry budget exhausted", RetryError)
+)
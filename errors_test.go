@@ -14,7 +14,17 @@ func TestErrorMessages(t *testing.T) {
 		{"invalid-threshold", ErrInvalidThresholdValue, []string{"threshold", "code"}},
 		{"invalid-duration", ErrInvalidDuration, []string{"duration", "code"}},
 		{"nil-function", ErrNilFunction, []string{"function", "code"}},
+		{"invalid-window-size", ErrInvalidWindowSize, []string{"window size", "code"}},
+		{"invalid-multiplier", ErrInvalidMultiplier, []string{"multiplier", "code"}},
+		{"nil-breaker", ErrNilBreaker, []string{"breaker", "code"}},
+		{"invalid-panic-policy", ErrInvalidPanicPolicy, []string{"panic policy", "code"}},
+		{"invalid-clock", ErrInvalidClock, []string{"clock", "code"}},
+		{"invalid-health-check", ErrInvalidHealthCheck, []string{"health checker", "code"}},
+		{"invalid-transition", ErrInvalidTransition, []string{"transition", "code"}},
+		{"invalid-logger", ErrInvalidLogger, []string{"logger", "code"}},
+		{"invalid-ramp-curve", ErrInvalidRampCurve, []string{"ramp curve", "code"}},
 		{"circuit-open", ErrCircuitOpen, []string{"circuit breaker is open", "code"}},
+		{"unhealthy", ErrUnhealthy, []string{"health check failed", "code"}},
 	}
 
 	for _, tc := range cases {
@@ -0,0 +1,148 @@
+package breaker
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/shuklasaharsh/circuitbreaker/storage"
+)
+
+// subscriptionBuffer is the number of events a subscriber may fall behind by
+// before further events are dropped for it.
+const subscriptionBuffer = 64
+
+// EventType identifies what an Event reports.
+type EventType uint8
+
+const (
+	// EventStateChange reports a committed state transition.
+	EventStateChange EventType = iota
+	// EventRejected reports a call rejected with ErrCircuitOpen.
+	EventRejected
+	// EventSuccess reports a call recorded as a success.
+	EventSuccess
+	// EventFailure reports a call recorded as a failure.
+	EventFailure
+	// EventIgnored reports a call whose outcome was not recorded.
+	EventIgnored
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventStateChange:
+		return "State Change"
+	case EventRejected:
+		return "Rejected"
+	case EventSuccess:
+		return "Success"
+	case EventFailure:
+		return "Failure"
+	case EventIgnored:
+		return "Ignored"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event describes something that happened to a breaker. Events are delivered
+// after the store update behind them has committed.
+type Event struct {
+	Type EventType
+	Name string
+	Time time.Time
+	// From and To are set for EventStateChange.
+	From State
+	To   State
+	// Record is the record as committed by the update behind the event.
+	Record storage.Record
+	// Err is the call error, or ErrCircuitOpen for EventRejected.
+	Err error
+	// Duration is how long the call ran, for call outcome events.
+	Duration time.Duration
+}
+
+type observer struct {
+	fn func(context.Context, Event)
+}
+
+// Observe registers fn to be called synchronously with every event, in the
+// goroutine that caused it and with the context of the call. No lock is held
+// while fn runs. The returned function removes the observer.
+func (b *Breaker) Observe(fn func(context.Context, Event)) (stop func()) {
+	if fn == nil {
+		return func() {}
+	}
+	o := &observer{fn: fn}
+	b.mu.Lock()
+	b.observers = append(slices.Clone(b.observers), o)
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			b.observers = slices.DeleteFunc(slices.Clone(b.observers), func(other *observer) bool {
+				return other == o
+			})
+			b.mu.Unlock()
+		})
+	}
+}
+
+// Subscribe returns a channel of the breaker's events that is closed once ctx
+// is done. Events are dropped for a subscriber that falls behind, so calls
+// through the breaker never wait on it.
+func (b *Breaker) Subscribe(ctx context.Context) <-chan Event {
+	ch := make(chan Event, subscriptionBuffer)
+	var mu sync.Mutex
+	closed := false
+	stop := b.Observe(func(_ context.Context, event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+		select {
+		case ch <- event:
+		default:
+		}
+	})
+
+	go func() {
+		<-ctx.Done()
+		stop()
+		mu.Lock()
+		closed = true
+		close(ch)
+		mu.Unlock()
+	}()
+	return ch
+}
+
+// emit delivers event to the observers registered when it is called.
+func (b *Breaker) emit(ctx context.Context, event Event) {
+	event.Name = b.Name
+	if event.Time.IsZero() {
+		event.Time = b.clock.Now()
+	}
+	b.mu.RLock()
+	observers := b.observers
+	b.mu.RUnlock()
+	for _, o := range observers {
+		o.fn(ctx, event)
+	}
+}
+
+func (b *Breaker) emitStateChange(ctx context.Context, from State, record storage.Record) {
+	if b.onStateChange != nil {
+		b.onStateChange(b.Name, from, record.State, record)
+	}
+	b.emit(ctx, Event{
+		Type:   EventStateChange,
+		From:   from,
+		To:     record.State,
+		Record: record,
+	})
+}
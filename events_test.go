@@ -0,0 +1,167 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shuklasaharsh/circuitbreaker/storage"
+)
+
+func TestWithOnStateChangeCalledAfterCommit(t *testing.T) {
+	type transition struct {
+		from, to State
+	}
+	var transitions []transition
+	var b *Breaker
+	b = New("svc",
+		WithFailureThreshold(1),
+		WithTimeout(time.Minute),
+		WithOnStateChange(func(name string, from, to State, record storage.Record) {
+			if name != "svc" {
+				t.Fatalf("expected breaker name, got %q", name)
+			}
+			if record.State != to {
+				t.Fatalf("expected record in %v, got %v", to, record.State)
+			}
+			// Reading the store here would deadlock if its lock were held.
+			stored, err := b.State(context.Background())
+			if err != nil {
+				t.Fatalf("state error: %v", err)
+			}
+			if stored != to {
+				t.Fatalf("expected committed state %v, got %v", to, stored)
+			}
+			transitions = append(transitions, transition{from, to})
+		}),
+	)
+	_ = b.Execute(func() error { return nil })
+	_ = b.Execute(func() error { return errors.New("boom") })
+
+	if len(transitions) != 1 || transitions[0] != (transition{StateClosed, StateOpen}) {
+		t.Fatalf("expected one closed->open transition, got %v", transitions)
+	}
+}
+
+func TestObserveReceivesEvents(t *testing.T) {
+	b := New("svc", WithFailureThreshold(1), WithTimeout(time.Minute))
+	var types []EventType
+	stop := b.Observe(func(_ context.Context, event Event) {
+		if event.Name != "svc" || event.Time.IsZero() {
+			t.Fatalf("expected named, timestamped event, got %#v", event)
+		}
+		types = append(types, event.Type)
+	})
+
+	boom := errors.New("boom")
+	_ = b.Execute(func() error { return nil })
+	_ = b.Execute(func() error { return boom })
+	_ = b.Execute(func() error { return nil })
+
+	expected := []EventType{EventSuccess, EventStateChange, EventFailure, EventRejected}
+	if len(types) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, types)
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, types)
+		}
+	}
+
+	stop()
+	stop()
+	_ = b.Execute(func() error { return nil })
+	if len(types) != len(expected) {
+		t.Fatalf("expected no events after stop, got %v", types)
+	}
+}
+
+func TestObserveFailureCarriesCallDetails(t *testing.T) {
+	b := New("svc")
+	boom := errors.New("boom")
+	var got Event
+	b.Observe(func(_ context.Context, event Event) {
+		if event.Type == EventFailure {
+			got = event
+		}
+	})
+	_ = b.Execute(func() error { return boom })
+
+	if !errors.Is(got.Err, boom) {
+		t.Fatalf("expected call error, got %v", got.Err)
+	}
+	if got.Record.Failures != 1 {
+		t.Fatalf("expected committed record, got %#v", got.Record)
+	}
+}
+
+func TestObserveNil(t *testing.T) {
+	b := New("svc")
+	b.Observe(nil)()
+	if err := b.Execute(func() error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSubscribeDeliversAndCloses(t *testing.T) {
+	b := New("svc")
+	ctx, cancel := context.WithCancel(context.Background())
+	events := b.Subscribe(ctx)
+
+	_ = b.Execute(func() error { return nil })
+	select {
+	case event := <-events:
+		if event.Type != EventSuccess {
+			t.Fatalf("expected success event, got %v", event.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for event")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Fatalf("expected closed channel")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for close")
+	}
+	_ = b.Execute(func() error { return nil })
+}
+
+func TestSubscribeDropsWhenFull(t *testing.T) {
+	b := New("svc")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := b.Subscribe(ctx)
+
+	for i := 0; i < subscriptionBuffer+10; i++ {
+		if err := b.Execute(func() error { return nil }); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(events) != subscriptionBuffer {
+		t.Fatalf("expected full buffer of %d, got %d", subscriptionBuffer, len(events))
+	}
+}
+
+func TestEventTypeString(t *testing.T) {
+	cases := []struct {
+		eventType EventType
+		expected  string
+	}{
+		{EventStateChange, "State Change"},
+		{EventRejected, "Rejected"},
+		{EventSuccess, "Success"},
+		{EventFailure, "Failure"},
+		{EventIgnored, "Ignored"},
+		{EventType(99), "Unknown"},
+	}
+	for _, tc := range cases {
+		if tc.eventType.String() != tc.expected {
+			t.Fatalf("expected %q, got %q", tc.expected, tc.eventType.String())
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package breaker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// HealthChecker reports whether the protected dependency looks healthy. While
+// a breaker is open it runs the checker in the background and leaves the open
+// state early once Check returns nil.
+type HealthChecker interface {
+	Check(ctx context.Context) error
+}
+
+// HealthCheckerFunc adapts a function to HealthChecker.
+type HealthCheckerFunc func(ctx context.Context) error
+
+func (f HealthCheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// HTTPHealthCheck sends a GET request to url and treats any 2xx response as
+// healthy.
+func HTTPHealthCheck(url string) HealthChecker {
+	return HealthCheckerFunc(func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("%w: %s returned %s", ErrUnhealthy, url, resp.Status)
+		}
+		return nil
+	})
+}
+
+// TCPHealthCheck treats the dependency as healthy when a TCP connection to
+// addr can be opened.
+func TCPHealthCheck(addr string) HealthChecker {
+	return HealthCheckerFunc(func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	})
+}
+
+// SQLPingHealthCheck treats the database as healthy when it answers a ping.
+func SQLPingHealthCheck(db *sql.DB) HealthChecker {
+	if db == nil {
+		panic(ErrInvalidHealthCheck)
+	}
+	return HealthCheckerFunc(db.PingContext)
+}
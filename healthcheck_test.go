@@ -0,0 +1,273 @@
+package breaker
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/shuklasaharsh/circuitbreaker/storage"
+)
+
+func TestHealthCheckMovesOpenToHalfOpen(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var healthy atomic.Bool
+		var checks atomic.Int64
+		b := New("svc",
+			WithFailureThreshold(1),
+			WithTimeout(time.Hour),
+			WithHealthCheck(HealthCheckerFunc(func(context.Context) error {
+				checks.Add(1)
+				if !healthy.Load() {
+					return errors.New("down")
+				}
+				return nil
+			})),
+			WithHealthCheckInterval(time.Second),
+		)
+		defer b.Close()
+
+		_ = b.Execute(func() error { return errors.New("boom") })
+
+		time.Sleep(3 * time.Second)
+		synctest.Wait()
+		if state, _ := b.State(context.Background()); state != StateOpen {
+			t.Fatalf("expected open while checks fail, got %v", state)
+		}
+		if checks.Load() != 3 {
+			t.Fatalf("expected 3 checks, got %d", checks.Load())
+		}
+
+		healthy.Store(true)
+		time.Sleep(time.Second)
+		synctest.Wait()
+		if state, _ := b.State(context.Background()); state != StateHalfOpen {
+			t.Fatalf("expected half-open after a passing check, got %v", state)
+		}
+
+		time.Sleep(5 * time.Second)
+		synctest.Wait()
+		if checks.Load() != 4 {
+			t.Fatalf("expected prober to stop once not open, got %d checks", checks.Load())
+		}
+	})
+}
+
+func TestHealthCheckTransitionClosed(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := New("svc",
+			WithFailureThreshold(1),
+			WithTimeout(time.Hour),
+			WithHealthCheck(HealthCheckerFunc(func(context.Context) error { return nil })),
+			WithHealthCheckTransition(StateClosed),
+		)
+		defer b.Close()
+
+		_ = b.Execute(func() error { return errors.New("boom") })
+		time.Sleep(defaultHealthCheckInterval)
+		synctest.Wait()
+
+		record, err := b.Snapshot(context.Background())
+		if err != nil {
+			t.Fatalf("snapshot error: %v", err)
+		}
+		if record.State != StateClosed || record.Trips != 0 {
+			t.Fatalf("expected closed with trips reset, got %v with %d", record.State, record.Trips)
+		}
+	})
+}
+
+func TestHealthCheckRestartsOnNextTrip(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var checks atomic.Int64
+		b := New("svc",
+			WithFailureThreshold(1),
+			WithSuccessThreshold(1),
+			WithTimeout(time.Hour),
+			WithHealthCheck(HealthCheckerFunc(func(context.Context) error {
+				checks.Add(1)
+				return nil
+			})),
+			WithHealthCheckInterval(time.Second),
+		)
+		defer b.Close()
+
+		for i := 1; i <= 2; i++ {
+			_ = b.Execute(func() error { return errors.New("boom") })
+			time.Sleep(time.Second)
+			synctest.Wait()
+			if state, _ := b.State(context.Background()); state != StateHalfOpen {
+				t.Fatalf("trip %d: expected half-open, got %v", i, state)
+			}
+			_ = b.Execute(func() error { return nil })
+		}
+		if checks.Load() != 2 {
+			t.Fatalf("expected one check per trip, got %d", checks.Load())
+		}
+	})
+}
+
+func TestHealthCheckRestartsOnTripDuringHandOff(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var checks atomic.Int64
+		var tripped atomic.Bool
+		var b *Breaker
+		b = New("svc",
+			WithFailureThreshold(1),
+			WithTimeout(time.Hour),
+			WithHealthCheck(HealthCheckerFunc(func(context.Context) error {
+				checks.Add(1)
+				return nil
+			})),
+			WithHealthCheckInterval(time.Second),
+			// A trial fails while the prober is still handing off.
+			WithOnStateChange(func(_ string, _, to State, _ storage.Record) {
+				if to == StateHalfOpen && tripped.CompareAndSwap(false, true) {
+					_ = b.Execute(func() error { return errors.New("boom") })
+				}
+			}),
+		)
+		defer b.Close()
+
+		_ = b.Execute(func() error { return errors.New("boom") })
+		time.Sleep(time.Second)
+		synctest.Wait()
+		if state, _ := b.State(context.Background()); state != StateOpen {
+			t.Fatalf("expected the trial to re-trip, got %v", state)
+		}
+
+		time.Sleep(time.Second)
+		synctest.Wait()
+		if checks.Load() != 2 {
+			t.Fatalf("expected probing to resume after the re-trip, got %d checks", checks.Load())
+		}
+		if state, _ := b.State(context.Background()); state != StateHalfOpen {
+			t.Fatalf("expected half-open after the second check, got %v", state)
+		}
+	})
+}
+
+func TestCloseStopsHealthCheck(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var checks atomic.Int64
+		b := New("svc",
+			WithFailureThreshold(1),
+			WithTimeout(time.Hour),
+			WithHealthCheck(HealthCheckerFunc(func(context.Context) error {
+				checks.Add(1)
+				return errors.New("down")
+			})),
+			WithHealthCheckInterval(time.Second),
+		)
+		_ = b.Execute(func() error { return errors.New("boom") })
+		time.Sleep(time.Second)
+		synctest.Wait()
+
+		if err := b.Close(); err != nil {
+			t.Fatalf("close error: %v", err)
+		}
+		_ = b.Execute(func() error { return nil })
+		time.Sleep(5 * time.Second)
+		synctest.Wait()
+		if checks.Load() != 1 {
+			t.Fatalf("expected no checks after Close, got %d", checks.Load())
+		}
+	})
+}
+
+func TestCloseWithoutHealthCheck(t *testing.T) {
+	if err := New("svc").Close(); err != nil {
+		t.Fatalf("close error: %v", err)
+	}
+}
+
+func TestHTTPHealthCheck(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	check := HTTPHealthCheck(server.URL)
+	if err := check.Check(context.Background()); err != nil {
+		t.Fatalf("expected healthy, got %v", err)
+	}
+	status = http.StatusServiceUnavailable
+	if err := check.Check(context.Background()); !errors.Is(err, ErrUnhealthy) {
+		t.Fatalf("expected ErrUnhealthy, got %v", err)
+	}
+}
+
+func TestTCPHealthCheck(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	addr := ln.Addr().String()
+	check := TCPHealthCheck(addr)
+	if err := check.Check(context.Background()); err != nil {
+		t.Fatalf("expected healthy, got %v", err)
+	}
+	_ = ln.Close()
+	if err := check.Check(context.Background()); err == nil {
+		t.Fatalf("expected dial error after listener closed")
+	}
+}
+
+type pingConnector struct {
+	err error
+}
+
+func (c pingConnector) Connect(context.Context) (driver.Conn, error) {
+	return pingConn(c), nil
+}
+
+func (c pingConnector) Driver() driver.Driver {
+	return nil
+}
+
+type pingConn struct {
+	err error
+}
+
+func (c pingConn) Ping(context.Context) error {
+	return c.err
+}
+
+func (pingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (pingConn) Close() error {
+	return nil
+}
+
+func (pingConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestSQLPingHealthCheck(t *testing.T) {
+	down := errors.New("down")
+	ok := sql.OpenDB(pingConnector{})
+	defer ok.Close()
+	if err := SQLPingHealthCheck(ok).Check(context.Background()); err != nil {
+		t.Fatalf("expected healthy, got %v", err)
+	}
+
+	failing := sql.OpenDB(pingConnector{err: down})
+	defer failing.Close()
+	if err := SQLPingHealthCheck(failing).Check(context.Background()); !errors.Is(err, down) {
+		t.Fatalf("expected ping error, got %v", err)
+	}
+}
+
+func TestSQLPingHealthCheckNilPanics(t *testing.T) {
+	assertPanics(t, func() { _ = SQLPingHealthCheck(nil) })
+}
@@ -0,0 +1,91 @@
+package breaker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/shuklasaharsh/circuitbreaker/storage"
+)
+
+const defaultRejectionLogInterval = time.Second
+
+// LogLevels sets the level of each kind of log record written by a breaker
+// configured with WithLogger.
+type LogLevels struct {
+	StateChange slog.Level
+	Rejection   slog.Level
+	StoreError  slog.Level
+}
+
+func defaultLogLevels() LogLevels {
+	return LogLevels{
+		StateChange: slog.LevelInfo,
+		Rejection:   slog.LevelWarn,
+		StoreError:  slog.LevelError,
+	}
+}
+
+// rejectionLog limits rejection records to one per interval and counts the
+// ones it drops.
+type rejectionLog struct {
+	mu         sync.Mutex
+	last       time.Time
+	suppressed int64
+}
+
+func (b *Breaker) logStateChange(ctx context.Context, from State, record storage.Record) {
+	if b.logger == nil {
+		return
+	}
+	attrs := append([]slog.Attr{
+		slog.String("breaker", b.Name),
+		slog.String("from", from.String()),
+		slog.String("to", record.State.String()),
+	}, recordAttrs(record)...)
+	b.logger.LogAttrs(ctx, b.logLevels.StateChange, "circuit breaker state changed", attrs...)
+}
+
+func (b *Breaker) logRejection(ctx context.Context, record storage.Record) {
+	if b.logger == nil {
+		return
+	}
+	now := b.clock.Now()
+	b.rejections.mu.Lock()
+	if !b.rejections.last.IsZero() && now.Sub(b.rejections.last) < b.rejectionLogInterval {
+		b.rejections.suppressed++
+		b.rejections.mu.Unlock()
+		return
+	}
+	suppressed := b.rejections.suppressed
+	b.rejections.last = now
+	b.rejections.suppressed = 0
+	b.rejections.mu.Unlock()
+
+	attrs := append([]slog.Attr{
+		slog.String("breaker", b.Name),
+		slog.String("state", record.State.String()),
+		slog.Int64("suppressed", suppressed),
+	}, recordAttrs(record)...)
+	b.logger.LogAttrs(ctx, b.logLevels.Rejection, "circuit breaker rejected call", attrs...)
+}
+
+func (b *Breaker) logStoreError(ctx context.Context, err error) {
+	if b.logger == nil {
+		return
+	}
+	b.logger.LogAttrs(ctx, b.logLevels.StoreError, "circuit breaker store update failed",
+		slog.String("breaker", b.Name),
+		slog.Any("error", err),
+	)
+}
+
+func recordAttrs(record storage.Record) []slog.Attr {
+	return []slog.Attr{
+		slog.Int64("failures", record.Failures),
+		slog.Int64("successes", record.Successes),
+		slog.Int64("slow_calls", record.SlowCalls),
+		slog.Int64("trips", record.Trips),
+	}
+}
@@ -0,0 +1,137 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shuklasaharsh/circuitbreaker/breakertest"
+)
+
+type captureHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *captureHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *captureHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *captureHandler) WithAttrs([]slog.Attr) slog.Handler {
+	return h
+}
+
+func (h *captureHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+func (h *captureHandler) messages(msg string) []slog.Record {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var out []slog.Record
+	for _, r := range h.records {
+		if r.Message == msg {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func attrs(r slog.Record) map[string]slog.Value {
+	out := make(map[string]slog.Value)
+	r.Attrs(func(a slog.Attr) bool {
+		out[a.Key] = a.Value
+		return true
+	})
+	return out
+}
+
+func TestLoggerLogsStateChange(t *testing.T) {
+	h := &captureHandler{}
+	b := New("svc", WithFailureThreshold(1), WithLogger(slog.New(h)))
+	_ = b.Execute(func() error { return errors.New("boom") })
+
+	records := h.messages("circuit breaker state changed")
+	if len(records) != 1 {
+		t.Fatalf("expected one state change record, got %d", len(records))
+	}
+	r := records[0]
+	if r.Level != slog.LevelInfo {
+		t.Fatalf("expected info level, got %v", r.Level)
+	}
+	got := attrs(r)
+	if got["breaker"].String() != "svc" || got["from"].String() != "Closed" || got["to"].String() != "Open" {
+		t.Fatalf("unexpected attributes: %v", got)
+	}
+	if got["trips"].Int64() != 1 {
+		t.Fatalf("expected trips counter, got %v", got["trips"])
+	}
+}
+
+func TestLoggerRateLimitsRejections(t *testing.T) {
+	h := &captureHandler{}
+	clock := breakertest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := New("svc",
+		WithFailureThreshold(1),
+		WithTimeout(time.Hour),
+		WithClock(clock),
+		WithLogger(slog.New(h)),
+		WithRejectionLogInterval(time.Second),
+	)
+	_ = b.Execute(func() error { return errors.New("boom") })
+
+	for range 3 {
+		_ = b.Execute(func() error { return nil })
+	}
+	if n := len(h.messages("circuit breaker rejected call")); n != 1 {
+		t.Fatalf("expected one rejection record within the interval, got %d", n)
+	}
+
+	clock.Advance(time.Second)
+	_ = b.Execute(func() error { return nil })
+	records := h.messages("circuit breaker rejected call")
+	if len(records) != 2 {
+		t.Fatalf("expected a second rejection record, got %d", len(records))
+	}
+	if records[1].Level != slog.LevelWarn {
+		t.Fatalf("expected warn level, got %v", records[1].Level)
+	}
+	if got := attrs(records[1])["suppressed"].Int64(); got != 2 {
+		t.Fatalf("expected 2 suppressed rejections, got %d", got)
+	}
+}
+
+func TestLoggerLogsStoreErrors(t *testing.T) {
+	h := &captureHandler{}
+	storeErr := errors.New("store down")
+	b := New("svc",
+		WithStorage(&updateErrorStore{err: storeErr}),
+		WithLogger(slog.New(h)),
+		WithLogLevels(LogLevels{StoreError: slog.LevelWarn}),
+	)
+	err := b.Execute(func() error { return errors.New("boom") })
+	if !errors.Is(err, storeErr) {
+		t.Fatalf("expected joined store error, got %v", err)
+	}
+
+	records := h.messages("circuit breaker store update failed")
+	if len(records) != 1 {
+		t.Fatalf("expected one store error record, got %d", len(records))
+	}
+	if records[0].Level != slog.LevelWarn {
+		t.Fatalf("expected configured level, got %v", records[0].Level)
+	}
+	if got := attrs(records[0])["error"].Any(); got != storeErr {
+		t.Fatalf("expected store error attribute, got %v", got)
+	}
+}
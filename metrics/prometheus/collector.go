@@ -0,0 +1,205 @@
+// Package prometheus exports circuit breaker metrics to Prometheus.
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	breaker "github.com/shuklasaharsh/circuitbreaker"
+	"github.com/shuklasaharsh/circuitbreaker/wrapper"
+)
+
+const namespace = "circuitbreaker"
+
+type Option func(*config)
+
+type config struct {
+	buckets []float64
+}
+
+// WithBuckets sets the latency histogram buckets, in seconds.
+func WithBuckets(buckets ...float64) Option {
+	return func(cfg *config) {
+		if len(buckets) > 0 {
+			cfg.buckets = buckets
+		}
+	}
+}
+
+// Collector is a prometheus.Collector for every breaker in a wrapper.Registry.
+// Breakers are labelled with the name they were registered under, and ones
+// registered after the collector was created are picked up as well.
+type Collector struct {
+	state       *prom.Desc
+	requests    *prom.CounterVec
+	failures    *prom.CounterVec
+	transitions *prom.CounterVec
+	latency     *prom.HistogramVec
+
+	mu       sync.Mutex
+	breakers map[string]tracked
+	stopHook func()
+}
+
+type tracked struct {
+	b    *breaker.Breaker
+	stop func()
+}
+
+// NewCollector starts recording the outcomes and latency of calls made
+// through the breakers in reg. Call Close to stop.
+func NewCollector(reg *wrapper.Registry, opts ...Option) (*Collector, error) {
+	if reg == nil {
+		return nil, wrapper.ErrInvalidRegistry
+	}
+	cfg := config{buckets: prom.DefBuckets}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	c := &Collector{
+		state: prom.NewDesc(
+			prom.BuildFQName(namespace, "", "state"),
+			"Current state (0=closed, 1=open, 2=half-open, 3=forced open, 4=forced closed, 5=disabled).",
+			[]string{"name"}, nil,
+		),
+		requests: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Total requests by outcome.",
+		}, []string{"name", "outcome"}),
+		failures: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "failures_total",
+			Help:      "Total failures.",
+		}, []string{"name"}),
+		transitions: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "state_transitions_total",
+			Help:      "State transitions.",
+		}, []string{"name", "from", "to"}),
+		latency: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: namespace,
+			Name:      "latency_seconds",
+			Help:      "Request latency.",
+			Buckets:   cfg.buckets,
+		}, []string{"name"}),
+		breakers: make(map[string]tracked),
+	}
+
+	stop, err := reg.OnRegister(c.track)
+	if err != nil {
+		return nil, err
+	}
+	c.stopHook = stop
+	return c, nil
+}
+
+// Handler serves the collector's metrics, for mounting on an existing server.
+// To combine them with other metrics, register the collector with a
+// prometheus.Registerer instead.
+func (c *Collector) Handler() http.Handler {
+	registry := prom.NewRegistry()
+	registry.MustRegister(c)
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Close stops recording. Metrics already recorded can still be collected.
+func (c *Collector) Close() {
+	c.stopHook()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range c.breakers {
+		t.stop()
+	}
+	clear(c.breakers)
+}
+
+func (c *Collector) Describe(ch chan<- *prom.Desc) {
+	ch <- c.state
+	c.requests.Describe(ch)
+	c.failures.Describe(ch)
+	c.transitions.Describe(ch)
+	c.latency.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prom.Metric) {
+	c.mu.Lock()
+	breakers := make(map[string]*breaker.Breaker, len(c.breakers))
+	for name, t := range c.breakers {
+		breakers[name] = t.b
+	}
+	c.mu.Unlock()
+
+	for name, b := range breakers {
+		state, err := b.State(context.Background())
+		if err != nil {
+			continue
+		}
+		ch <- prom.MustNewConstMetric(c.state, prom.GaugeValue, float64(state), name)
+	}
+	c.requests.Collect(ch)
+	c.failures.Collect(ch)
+	c.transitions.Collect(ch)
+	c.latency.Collect(ch)
+}
+
+// track observes b under name, replacing a breaker previously registered
+// under the same name.
+func (c *Collector) track(name string, b *breaker.Breaker) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t, ok := c.breakers[name]; ok {
+		if t.b == b {
+			return
+		}
+		t.stop()
+	}
+	stop := b.Observe(func(_ context.Context, event breaker.Event) {
+		c.observe(name, event)
+	})
+	c.breakers[name] = tracked{b: b, stop: stop}
+}
+
+func (c *Collector) observe(name string, event breaker.Event) {
+	switch event.Type {
+	case breaker.EventStateChange:
+		c.transitions.WithLabelValues(name, stateLabel(event.From), stateLabel(event.To)).Inc()
+		return
+	case breaker.EventRejected:
+		c.requests.WithLabelValues(name, "rejected").Inc()
+		return
+	case breaker.EventSuccess:
+		c.requests.WithLabelValues(name, "success").Inc()
+	case breaker.EventFailure:
+		c.requests.WithLabelValues(name, "failure").Inc()
+		c.failures.WithLabelValues(name).Inc()
+	case breaker.EventIgnored:
+		c.requests.WithLabelValues(name, "ignored").Inc()
+	default:
+		return
+	}
+	c.latency.WithLabelValues(name).Observe(event.Duration.Seconds())
+}
+
+func stateLabel(state breaker.State) string {
+	switch state {
+	case breaker.StateClosed:
+		return "closed"
+	case breaker.StateOpen:
+		return "open"
+	case breaker.StateHalfOpen:
+		return "half_open"
+	case breaker.StateForcedOpen:
+		return "forced_open"
+	case breaker.StateForcedClosed:
+		return "forced_closed"
+	case breaker.StateDisabled:
+		return "disabled"
+	default:
+		return "unknown"
+	}
+}
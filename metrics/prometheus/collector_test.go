@@ -0,0 +1,106 @@
+package prometheus
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	breaker "github.com/shuklasaharsh/circuitbreaker"
+	"github.com/shuklasaharsh/circuitbreaker/wrapper"
+)
+
+func TestNewCollectorNilRegistry(t *testing.T) {
+	if _, err := NewCollector(nil); err != wrapper.ErrInvalidRegistry {
+		t.Fatalf("expected ErrInvalidRegistry, got %v", err)
+	}
+}
+
+func TestCollectorRecordsOutcomes(t *testing.T) {
+	reg := wrapper.NewRegistry()
+	c, err := NewCollector(reg)
+	if err != nil {
+		t.Fatalf("new collector error: %v", err)
+	}
+	defer c.Close()
+
+	// Registered after the collector, under a different name.
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithTimeout(time.Minute),
+	)
+	_ = reg.RegisterBreakerWithName("api", cb)
+
+	_ = cb.Execute(func() error { return nil })
+	_ = cb.Execute(func() error { return errors.New("boom") })
+	_ = cb.Execute(func() error { return nil })
+
+	for outcome, want := range map[string]float64{"success": 1, "failure": 1, "rejected": 1} {
+		if got := testutil.ToFloat64(c.requests.WithLabelValues("api", outcome)); got != want {
+			t.Fatalf("expected %v %s requests, got %v", want, outcome, got)
+		}
+	}
+	if got := testutil.ToFloat64(c.failures.WithLabelValues("api")); got != 1 {
+		t.Fatalf("expected 1 failure, got %v", got)
+	}
+	if got := testutil.ToFloat64(c.transitions.WithLabelValues("api", "closed", "open")); got != 1 {
+		t.Fatalf("expected 1 closed->open transition, got %v", got)
+	}
+	if got := testutil.CollectAndCount(c.latency); got != 1 {
+		t.Fatalf("expected 1 latency series, got %d", got)
+	}
+}
+
+func TestCollectorReplacesBreaker(t *testing.T) {
+	reg := wrapper.NewRegistry()
+	old := breaker.New("svc")
+	_ = reg.RegisterBreaker(old)
+	c, _ := NewCollector(reg)
+	defer c.Close()
+
+	_ = reg.RegisterBreaker(breaker.New("svc"))
+	_ = old.Execute(func() error { return nil })
+
+	if got := testutil.ToFloat64(c.requests.WithLabelValues("svc", "success")); got != 0 {
+		t.Fatalf("expected replaced breaker to be ignored, got %v", got)
+	}
+}
+
+func TestCollectorHandlerServesState(t *testing.T) {
+	reg := wrapper.NewRegistry()
+	cb := breaker.New("svc", breaker.WithFailureThreshold(1), breaker.WithTimeout(time.Minute))
+	_ = reg.RegisterBreaker(cb)
+	c, _ := NewCollector(reg)
+	defer c.Close()
+	_ = cb.Execute(func() error { return errors.New("boom") })
+
+	rec := httptest.NewRecorder()
+	c.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	for _, want := range []string{
+		`circuitbreaker_state{name="svc"} 1`,
+		`circuitbreaker_requests_total{name="svc",outcome="failure"} 1`,
+		`circuitbreaker_latency_seconds_count{name="svc"} 1`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Fatalf("expected %q in output:\n%s", want, body)
+		}
+	}
+}
+
+func TestCollectorCloseStopsRecording(t *testing.T) {
+	reg := wrapper.NewRegistry()
+	cb := breaker.New("svc")
+	_ = reg.RegisterBreaker(cb)
+	c, _ := NewCollector(reg)
+	c.Close()
+
+	_ = cb.Execute(func() error { return nil })
+	if got := testutil.ToFloat64(c.requests.WithLabelValues("svc", "success")); got != 0 {
+		t.Fatalf("expected no requests after Close, got %v", got)
+	}
+}
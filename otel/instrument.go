@@ -0,0 +1,148 @@
+// Package otel records circuit breaker metrics and span events with
+// OpenTelemetry.
+package otel
+
+import (
+	"context"
+
+	breaker "github.com/shuklasaharsh/circuitbreaker"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const scope = "github.com/shuklasaharsh/circuitbreaker/otel"
+
+type Option func(*config)
+
+type config struct {
+	meterProvider metric.MeterProvider
+}
+
+// WithMeterProvider records metrics with mp instead of the global provider.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(cfg *config) {
+		if mp != nil {
+			cfg.meterProvider = mp
+		}
+	}
+}
+
+type instruments struct {
+	name     attribute.KeyValue
+	calls    metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+// Instrument records b's state, calls and call durations, and adds events to
+// the span active in the context of a call when the call is rejected, fails or
+// changes the breaker's state. Call stop to remove the instrumentation.
+func Instrument(b *breaker.Breaker, opts ...Option) (stop func(), err error) {
+	if b == nil {
+		return nil, breaker.ErrNilBreaker
+	}
+	cfg := config{meterProvider: otel.GetMeterProvider()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	meter := cfg.meterProvider.Meter(scope)
+
+	inst := instruments{name: attribute.String("circuitbreaker.name", b.Name)}
+	inst.calls, err = meter.Int64Counter("circuitbreaker.calls",
+		metric.WithDescription("Calls made through the breaker, by outcome."),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	inst.duration, err = meter.Float64Histogram("circuitbreaker.call.duration",
+		metric.WithDescription("Duration of calls admitted by the breaker."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	state, err := meter.Int64ObservableGauge("circuitbreaker.state",
+		metric.WithDescription("Current state (0=closed, 1=open, 2=half-open, 3=forced open, 4=forced closed, 5=disabled)."),
+	)
+	if err != nil {
+		return nil, err
+	}
+	registration, err := meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		current, err := b.State(ctx)
+		if err != nil {
+			return err
+		}
+		o.ObserveInt64(state, int64(current), metric.WithAttributes(inst.name))
+		return nil
+	}, state)
+	if err != nil {
+		return nil, err
+	}
+
+	stopObserving := b.Observe(inst.observe)
+	return func() {
+		stopObserving()
+		_ = registration.Unregister()
+	}, nil
+}
+
+func (inst instruments) observe(ctx context.Context, event breaker.Event) {
+	span := trace.SpanFromContext(ctx)
+	switch event.Type {
+	case breaker.EventStateChange:
+		span.AddEvent("circuitbreaker.state_change", trace.WithAttributes(
+			inst.name,
+			attribute.String("circuitbreaker.from", stateLabel(event.From)),
+			attribute.String("circuitbreaker.to", stateLabel(event.To)),
+		))
+	case breaker.EventRejected:
+		inst.calls.Add(ctx, 1, metric.WithAttributes(inst.name, outcome("rejected")))
+		span.AddEvent("circuitbreaker.rejected", trace.WithAttributes(
+			inst.name,
+			attribute.String("circuitbreaker.state", stateLabel(event.Record.State)),
+		))
+	case breaker.EventSuccess:
+		inst.record(ctx, event, "success")
+	case breaker.EventFailure:
+		inst.record(ctx, event, "failure")
+		attrs := []attribute.KeyValue{inst.name}
+		if event.Err != nil {
+			attrs = append(attrs, attribute.String("error.message", event.Err.Error()))
+		}
+		span.AddEvent("circuitbreaker.failure", trace.WithAttributes(attrs...))
+	case breaker.EventIgnored:
+		inst.record(ctx, event, "ignored")
+	}
+}
+
+// record counts an admitted call and its duration.
+func (inst instruments) record(ctx context.Context, event breaker.Event, result string) {
+	attrs := metric.WithAttributes(inst.name, outcome(result))
+	inst.calls.Add(ctx, 1, attrs)
+	inst.duration.Record(ctx, event.Duration.Seconds(), attrs)
+}
+
+func outcome(result string) attribute.KeyValue {
+	return attribute.String("circuitbreaker.outcome", result)
+}
+
+func stateLabel(state breaker.State) string {
+	switch state {
+	case breaker.StateClosed:
+		return "closed"
+	case breaker.StateOpen:
+		return "open"
+	case breaker.StateHalfOpen:
+		return "half_open"
+	case breaker.StateForcedOpen:
+		return "forced_open"
+	case breaker.StateForcedClosed:
+		return "forced_closed"
+	case breaker.StateDisabled:
+		return "disabled"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,163 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	breaker "github.com/shuklasaharsh/circuitbreaker"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect error: %v", err)
+	}
+	got := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			got[m.Name] = m.Data
+		}
+	}
+	return got
+}
+
+func TestInstrumentNilBreaker(t *testing.T) {
+	if _, err := Instrument(nil); !errors.Is(err, breaker.ErrNilBreaker) {
+		t.Fatalf("expected ErrNilBreaker, got %v", err)
+	}
+}
+
+func TestInstrumentRecordsMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithTimeout(time.Minute),
+	)
+	stop, err := Instrument(cb, WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	if err != nil {
+		t.Fatalf("instrument error: %v", err)
+	}
+	defer stop()
+
+	_ = cb.Execute(func() error { return nil })
+	_ = cb.Execute(func() error { return errors.New("boom") })
+	_ = cb.Execute(func() error { return nil })
+
+	metrics := collect(t, reader)
+
+	calls, ok := metrics["circuitbreaker.calls"].(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("expected calls counter, got %T", metrics["circuitbreaker.calls"])
+	}
+	outcomes := make(map[string]int64)
+	for _, dp := range calls.DataPoints {
+		value, _ := dp.Attributes.Value("circuitbreaker.outcome")
+		outcomes[value.AsString()] = dp.Value
+	}
+	for _, outcome := range []string{"success", "failure", "rejected"} {
+		if outcomes[outcome] != 1 {
+			t.Fatalf("expected one %s call, got %v", outcome, outcomes)
+		}
+	}
+
+	duration, ok := metrics["circuitbreaker.call.duration"].(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatalf("expected duration histogram, got %T", metrics["circuitbreaker.call.duration"])
+	}
+	var count uint64
+	for _, dp := range duration.DataPoints {
+		count += dp.Count
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 recorded durations, got %d", count)
+	}
+
+	state, ok := metrics["circuitbreaker.state"].(metricdata.Gauge[int64])
+	if !ok || len(state.DataPoints) != 1 {
+		t.Fatalf("expected one state data point, got %#v", metrics["circuitbreaker.state"])
+	}
+	dp := state.DataPoints[0]
+	if dp.Value != int64(breaker.StateOpen) {
+		t.Fatalf("expected open state, got %d", dp.Value)
+	}
+	if name, _ := dp.Attributes.Value("circuitbreaker.name"); name.AsString() != "svc" {
+		t.Fatalf("expected breaker name attribute, got %v", name)
+	}
+}
+
+func TestInstrumentAddsSpanEvents(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithTimeout(time.Minute),
+	)
+	stop, err := Instrument(cb, WithMeterProvider(sdkmetric.NewMeterProvider()))
+	if err != nil {
+		t.Fatalf("instrument error: %v", err)
+	}
+	defer stop()
+
+	ctx, span := tracer.Start(context.Background(), "call")
+	_ = cb.ExecuteContext(ctx, func() error { return errors.New("boom") })
+	_ = cb.ExecuteContext(ctx, func() error { return nil })
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected one span, got %d", len(spans))
+	}
+	var names []string
+	for _, event := range spans[0].Events() {
+		names = append(names, event.Name)
+	}
+	want := []string{"circuitbreaker.state_change", "circuitbreaker.failure", "circuitbreaker.rejected"}
+	if len(names) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("expected events %v, got %v", want, names)
+		}
+	}
+	failure := spans[0].Events()[1]
+	if !hasAttribute(failure.Attributes, attribute.String("error.message", "boom")) {
+		t.Fatalf("expected error message on failure event, got %v", failure.Attributes)
+	}
+}
+
+func TestInstrumentStop(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	cb := breaker.New("svc")
+	stop, err := Instrument(cb, WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	if err != nil {
+		t.Fatalf("instrument error: %v", err)
+	}
+	stop()
+	_ = cb.Execute(func() error { return nil })
+
+	metrics := collect(t, reader)
+	if calls, ok := metrics["circuitbreaker.calls"].(metricdata.Sum[int64]); ok && len(calls.DataPoints) > 0 {
+		t.Fatalf("expected no calls after stop, got %v", calls.DataPoints)
+	}
+	if state, ok := metrics["circuitbreaker.state"].(metricdata.Gauge[int64]); ok && len(state.DataPoints) > 0 {
+		t.Fatalf("expected no state after stop, got %v", state.DataPoints)
+	}
+}
+
+func hasAttribute(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, attr := range attrs {
+		if attr == want {
+			return true
+		}
+	}
+	return false
+}
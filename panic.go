@@ -0,0 +1,49 @@
+package breaker
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicPolicy decides what ExecuteContext does after recording a panic raised
+// by the wrapped function.
+type PanicPolicy uint8
+
+const (
+	// PanicRepanic re-raises the original panic value once it is recorded.
+	PanicRepanic PanicPolicy = iota
+	// PanicReturnError returns a *PanicError instead of panicking.
+	PanicReturnError
+)
+
+// PanicError carries a value recovered from a panicking call and the stack of
+// the goroutine that panicked. It is classified like any other call error, so
+// WithErrorClassifier or WithIgnoredErrorType[*PanicError] control whether a
+// panic counts as a failure.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("circuit breaker call panicked: %v", e.Value)
+}
+
+// Unwrap returns the panic value when it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// call runs fn and converts a panic raised by it into a *PanicError.
+func call(fn func() error) (panicked bool, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+			panicked = true
+		}
+	}()
+	return false, fn()
+}
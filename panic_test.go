@@ -0,0 +1,98 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExecutePanicRecordsFailureAndRepanics(t *testing.T) {
+	b := New("svc", WithFailureThreshold(1), WithTimeout(time.Minute))
+
+	func() {
+		defer func() {
+			if v := recover(); v != "boom" {
+				t.Fatalf("expected original panic value, got %v", v)
+			}
+		}()
+		_ = b.Execute(func() error { panic("boom") })
+	}()
+
+	state, err := b.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != StateOpen {
+		t.Fatalf("expected open after panic, got %v", state)
+	}
+}
+
+func TestExecutePanicReturnError(t *testing.T) {
+	b := New("svc", WithFailureThreshold(1), WithPanicPolicy(PanicReturnError))
+	cause := errors.New("cause")
+
+	err := b.Execute(func() error { panic(cause) })
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected *PanicError, got %v", err)
+	}
+	if panicErr.Value != cause || len(panicErr.Stack) == 0 {
+		t.Fatalf("expected panic value and stack, got %#v", panicErr)
+	}
+	if !errors.Is(err, cause) {
+		t.Fatalf("expected panic error to unwrap to cause")
+	}
+
+	state, err := b.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != StateOpen {
+		t.Fatalf("expected open after panic, got %v", state)
+	}
+}
+
+func TestExecutePanicIgnoredByType(t *testing.T) {
+	b := New("svc",
+		WithFailureThreshold(1),
+		WithPanicPolicy(PanicReturnError),
+		WithIgnoredErrorType[*PanicError](),
+	)
+	_ = b.Execute(func() error { panic("boom") })
+
+	state, err := b.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != StateClosed {
+		t.Fatalf("expected ignored panic to leave breaker closed, got %v", state)
+	}
+}
+
+func TestExecuteReturnedPanicErrorDoesNotRepanic(t *testing.T) {
+	b := New("svc")
+	returned := &PanicError{Value: "inner"}
+	if err := b.Execute(func() error { return returned }); err != returned {
+		t.Fatalf("expected returned error, got %v", err)
+	}
+}
+
+func TestPanicErrorUnwrapNonError(t *testing.T) {
+	err := &PanicError{Value: 42}
+	if err.Unwrap() != nil {
+		t.Fatalf("expected nil unwrap for non-error value")
+	}
+	if err.Error() == "" {
+		t.Fatalf("expected message")
+	}
+}
+
+func TestBreakerPanicPolicy(t *testing.T) {
+	if p := New("svc").PanicPolicy(); p != PanicRepanic {
+		t.Fatalf("expected PanicRepanic by default, got %v", p)
+	}
+	if p := New("svc", WithPanicPolicy(PanicReturnError)).PanicPolicy(); p != PanicReturnError {
+		t.Fatalf("expected PanicReturnError, got %v", p)
+	}
+}
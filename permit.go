@@ -0,0 +1,103 @@
+package breaker
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/shuklasaharsh/circuitbreaker/storage"
+)
+
+// Permit is a call admitted by Allow. Once the call finishes, exactly one of
+// Success, Failure or Cancel reports how it went; later reports are no-ops.
+type Permit struct {
+	b     *Breaker
+	ctx   context.Context
+	state State
+	start time.Time
+	done  *atomic.Bool
+}
+
+// Allow asks the breaker to admit a call that cannot be wrapped in a closure.
+// It returns ErrCircuitOpen when the call is rejected. The permit remembers the
+// state it was admitted in, so a half-open trial permit is returned correctly.
+func (b *Breaker) Allow(ctx context.Context) (Permit, error) {
+	adm, err := b.allow(ctx)
+	if err != nil {
+		return Permit{}, err
+	}
+	if !adm.allowed {
+		b.logRejection(ctx, adm.record)
+		b.emit(ctx, Event{Type: EventRejected, Record: adm.record, Err: ErrCircuitOpen})
+		return Permit{}, ErrCircuitOpen
+	}
+	return Permit{
+		b:     b,
+		ctx:   ctx,
+		state: adm.state,
+		start: b.clock.Now(),
+		done:  new(atomic.Bool),
+	}, nil
+}
+
+// State returns the state the permit was admitted in.
+func (p Permit) State() State {
+	return p.state
+}
+
+// Success records the call as a success.
+func (p Permit) Success() error {
+	return p.finish(OutcomeSuccess, nil)
+}
+
+// Failure records the call as failed with err. The error goes through the
+// breaker's classification, so ignored errors leave the counters untouched.
+func (p Permit) Failure(err error) error {
+	outcome := OutcomeFailure
+	if err != nil && p.b != nil {
+		outcome = p.b.classify(err)
+	}
+	return p.finish(outcome, err)
+}
+
+// Cancel returns the permit without recording an outcome.
+func (p Permit) Cancel() error {
+	return p.finish(OutcomeIgnore, nil)
+}
+
+// finish records outcome for the call once and announces it.
+func (p Permit) finish(outcome Outcome, err error) error {
+	if p.b == nil || !p.done.CompareAndSwap(false, true) {
+		return nil
+	}
+	b := p.b
+	elapsed := b.clock.Now().Sub(p.start)
+	slow := b.slowCallDuration > 0 && elapsed > b.slowCallDuration
+	if p.state == storage.StateDisabled {
+		outcome = OutcomeIgnore
+	}
+
+	// A cancelled call must still be recorded, or a half-open trial permit
+	// would never be returned to a store that honours ctx.
+	ctx := context.WithoutCancel(p.ctx)
+	var record storage.Record
+	var recordErr error
+	event := Event{Err: err, Duration: elapsed}
+	switch outcome {
+	case OutcomeSuccess:
+		event.Type = EventSuccess
+		record, recordErr = b.onSuccess(ctx, p.state, slow)
+	case OutcomeIgnore:
+		event.Type = EventIgnored
+		record, recordErr = b.onIgnore(ctx, p.state)
+	default:
+		event.Type = EventFailure
+		record, recordErr = b.onFailure(ctx, p.state, slow)
+	}
+	if recordErr != nil {
+		return recordErr
+	}
+	event.Record = record
+	b.emit(p.ctx, event)
+	return nil
+}
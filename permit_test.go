@@ -0,0 +1,191 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shuklasaharsh/circuitbreaker/breakertest"
+	"github.com/shuklasaharsh/circuitbreaker/storage"
+)
+
+func TestAllowSuccess(t *testing.T) {
+	ctx := context.Background()
+	b := New("svc")
+	permit, err := b.Allow(ctx)
+	if err != nil {
+		t.Fatalf("allow error: %v", err)
+	}
+	if permit.State() != StateClosed {
+		t.Fatalf("expected closed permit, got %v", permit.State())
+	}
+	if err := permit.Success(); err != nil {
+		t.Fatalf("success error: %v", err)
+	}
+}
+
+func TestAllowFailureTripsBreaker(t *testing.T) {
+	ctx := context.Background()
+	b := New("svc", WithFailureThreshold(1), WithTimeout(time.Minute))
+	permit, err := b.Allow(ctx)
+	if err != nil {
+		t.Fatalf("allow error: %v", err)
+	}
+	if err := permit.Failure(errors.New("boom")); err != nil {
+		t.Fatalf("failure error: %v", err)
+	}
+
+	rejected, err := b.Allow(ctx)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if err := rejected.Success(); err != nil {
+		t.Fatalf("expected rejected permit to be a no-op, got %v", err)
+	}
+}
+
+func TestPermitReportsOnce(t *testing.T) {
+	ctx := context.Background()
+	b := New("svc", WithFailureThreshold(1))
+	permit, err := b.Allow(ctx)
+	if err != nil {
+		t.Fatalf("allow error: %v", err)
+	}
+	_ = permit.Success()
+	_ = permit.Failure(errors.New("boom"))
+
+	state, err := b.State(ctx)
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != StateClosed {
+		t.Fatalf("expected second report to be ignored, got %v", state)
+	}
+}
+
+func TestPermitFailureUsesClassification(t *testing.T) {
+	ctx := context.Background()
+	b := New("svc", WithFailureThreshold(1), WithIgnoredErrors(context.Canceled))
+	permit, err := b.Allow(ctx)
+	if err != nil {
+		t.Fatalf("allow error: %v", err)
+	}
+	_ = permit.Failure(context.Canceled)
+
+	state, err := b.State(ctx)
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != StateClosed {
+		t.Fatalf("expected ignored failure, got %v", state)
+	}
+}
+
+func TestPermitCancelReturnsHalfOpenPermit(t *testing.T) {
+	ctx := context.Background()
+	timeout := 5 * time.Millisecond
+	b := New("svc",
+		WithFailureThreshold(1),
+		WithTimeout(timeout),
+		WithHalfOpenMaxCalls(1),
+	)
+	_ = b.Execute(func() error { return errors.New("boom") })
+	time.Sleep(timeout + time.Millisecond)
+
+	permit, err := b.Allow(ctx)
+	if err != nil {
+		t.Fatalf("allow error: %v", err)
+	}
+	if permit.State() != StateHalfOpen {
+		t.Fatalf("expected half-open permit, got %v", permit.State())
+	}
+	if _, err := b.Allow(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected trial slot taken, got %v", err)
+	}
+	if err := permit.Cancel(); err != nil {
+		t.Fatalf("cancel error: %v", err)
+	}
+	if _, err := b.Allow(ctx); err != nil {
+		t.Fatalf("expected trial slot returned, got %v", err)
+	}
+}
+
+// ctxStore fails updates once their context is done, like the network stores.
+type ctxStore struct {
+	*storage.MemoryStore
+}
+
+func (s ctxStore) Update(ctx context.Context, name string, fn func(storage.Record) (storage.Record, error)) (storage.Record, error) {
+	if err := ctx.Err(); err != nil {
+		return storage.Record{}, err
+	}
+	return s.MemoryStore.Update(ctx, name, fn)
+}
+
+func TestCancelledCallReturnsHalfOpenPermit(t *testing.T) {
+	clock := breakertest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := New("svc",
+		WithFailureThreshold(1),
+		WithTimeout(time.Minute),
+		WithHalfOpenMaxCalls(1),
+		WithStorage(ctxStore{storage.NewMemoryStore()}),
+		WithClock(clock),
+	)
+	_ = b.Execute(func() error { return errors.New("boom") })
+	clock.Advance(time.Minute + time.Nanosecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := b.ExecuteCtx(ctx, func(context.Context) error {
+		cancel()
+		return context.Canceled
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	record, err := b.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if record.HalfOpenCalls != 0 {
+		t.Fatalf("expected trial permit returned, got %d in flight", record.HalfOpenCalls)
+	}
+	if _, err := b.Allow(context.Background()); err != nil {
+		t.Fatalf("expected trial slot available, got %v", err)
+	}
+}
+
+func TestLeakedHalfOpenPermitIsReclaimed(t *testing.T) {
+	ctx := context.Background()
+	clock := breakertest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := New("svc",
+		WithFailureThreshold(1),
+		WithTimeout(time.Minute),
+		WithHalfOpenMaxCalls(1),
+		WithClock(clock),
+	)
+	_ = b.Execute(func() error { return errors.New("boom") })
+	clock.Advance(time.Minute + time.Nanosecond)
+
+	// The trial permit is dropped without reporting.
+	if _, err := b.Allow(ctx); err != nil {
+		t.Fatalf("allow error: %v", err)
+	}
+	if _, err := b.Allow(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected trial slot taken, got %v", err)
+	}
+
+	clock.Advance(time.Minute + time.Nanosecond)
+	permit, err := b.Allow(ctx)
+	if err != nil {
+		t.Fatalf("expected leaked trial slot reclaimed, got %v", err)
+	}
+	if permit.State() != StateHalfOpen {
+		t.Fatalf("expected half-open permit, got %v", permit.State())
+	}
+	_ = permit.Success()
+	_ = b.Execute(func() error { return nil })
+	if state, _ := b.State(ctx); state != StateClosed {
+		t.Fatalf("expected breaker to close after trials, got %v", state)
+	}
+}
@@ -0,0 +1,9 @@
+package policy
+
+import "github.com/shuklasaharsh/circuitbreaker/errors"
+
+var (
+	ErrNilStrategy     = errors.NewError(100, "strategy cannot be nil", errors.ConfigError)
+	ErrNilFunction     = errors.NewError(101, "function cannot be nil", errors.ConfigError)
+	ErrInvalidDuration = errors.NewError(102, "supplied timeout duration is invalid", errors.ConfigError)
+)
@@ -0,0 +1,94 @@
+// Package policy chains resilience strategies, such as a timeout, retries, a
+// circuit breaker, a bulkhead and a fallback, into a single policy.
+package policy
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	breaker "github.com/shuklasaharsh/circuitbreaker"
+	"github.com/shuklasaharsh/circuitbreaker/bulkhead"
+	"github.com/shuklasaharsh/circuitbreaker/retry"
+)
+
+// Strategy runs a call with one resilience behaviour. *breaker.Breaker,
+// *bulkhead.Bulkhead, *retry.Retrier and *Policy all satisfy it.
+type Strategy interface {
+	ExecuteCtx(ctx context.Context, fn func(context.Context) error) error
+}
+
+// StrategyFunc adapts a function to Strategy.
+type StrategyFunc func(ctx context.Context, fn func(context.Context) error) error
+
+func (f StrategyFunc) ExecuteCtx(ctx context.Context, fn func(context.Context) error) error {
+	return f(ctx, fn)
+}
+
+// Policy runs calls through a chain of strategies.
+type Policy struct {
+	strategies []Strategy
+}
+
+// New chains strategies in a fixed order, whatever order they are passed in,
+// from the outermost to the innermost:
+//
+//	Fallback, Timeout, *retry.Retrier, *breaker.Breaker, *bulkhead.Bulkhead, others
+//
+// so the fallback covers rejections and failures the breaker has already
+// recorded, the timeout bounds every attempt together, and each retry passes
+// the breaker and then the bulkhead. Other strategies, including nested
+// policies, wrap the call itself and keep the order they are passed in.
+func New(strategies ...Strategy) *Policy {
+	for _, s := range strategies {
+		if s == nil {
+			panic(ErrNilStrategy)
+		}
+	}
+	ordered := slices.Clone(strategies)
+	slices.SortStableFunc(ordered, func(a, b Strategy) int {
+		return cmp.Compare(rank(a), rank(b))
+	})
+	return &Policy{strategies: ordered}
+}
+
+// rank returns the position of s in the chain.
+func rank(s Strategy) int {
+	switch s.(type) {
+	case fallback:
+		return 0
+	case timeout:
+		return 1
+	case *retry.Retrier:
+		return 2
+	case *breaker.Breaker:
+		return 3
+	case *bulkhead.Bulkhead:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// Execute runs fn through the chain. Each strategy passes the context it
+// derives to the next, and fn receives the innermost one.
+func (p *Policy) Execute(ctx context.Context, fn func(context.Context) error) error {
+	if fn == nil {
+		return ErrNilFunction
+	}
+	return p.run(ctx, 0, fn)
+}
+
+// ExecuteCtx is Execute, so a Policy can be nested as a Strategy.
+func (p *Policy) ExecuteCtx(ctx context.Context, fn func(context.Context) error) error {
+	return p.Execute(ctx, fn)
+}
+
+func (p *Policy) run(ctx context.Context, i int, fn func(context.Context) error) error {
+	if i == len(p.strategies) {
+		return fn(ctx)
+	}
+	return p.strategies[i].ExecuteCtx(ctx, func(ctx context.Context) error {
+		return p.run(ctx, i+1, fn)
+	})
+}
@@ -0,0 +1,211 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	breaker "github.com/shuklasaharsh/circuitbreaker"
+	"github.com/shuklasaharsh/circuitbreaker/bulkhead"
+	"github.com/shuklasaharsh/circuitbreaker/retry"
+)
+
+type key struct{}
+
+// tracer records when each named strategy starts and ends, and tags the
+// context it passes on.
+func tracer(name string, trace *[]string) Strategy {
+	return StrategyFunc(func(ctx context.Context, fn func(context.Context) error) error {
+		*trace = append(*trace, "enter "+name)
+		err := fn(context.WithValue(ctx, key{}, name))
+		*trace = append(*trace, "exit "+name)
+		return err
+	})
+}
+
+func TestNewNilStrategyPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic")
+		}
+	}()
+	_ = New(nil)
+}
+
+func TestExecuteNilFunction(t *testing.T) {
+	if err := New().Execute(context.Background(), nil); !errors.Is(err, ErrNilFunction) {
+		t.Fatalf("expected ErrNilFunction, got %v", err)
+	}
+}
+
+func TestExecuteAppliesStrategiesOutermostFirst(t *testing.T) {
+	var trace []string
+	p := New(tracer("outer", &trace), tracer("inner", &trace))
+
+	var got any
+	err := p.Execute(context.Background(), func(ctx context.Context) error {
+		got = ctx.Value(key{})
+		trace = append(trace, "call")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"enter outer", "enter inner", "call", "exit inner", "exit outer"}
+	if len(trace) != len(want) {
+		t.Fatalf("expected %v, got %v", want, trace)
+	}
+	for i := range want {
+		if trace[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, trace)
+		}
+	}
+	if got != "inner" {
+		t.Fatalf("expected the innermost context, got %v", got)
+	}
+}
+
+func TestPolicyNests(t *testing.T) {
+	var trace []string
+	inner := New(tracer("b", &trace))
+	p := New(tracer("a", &trace), inner)
+	_ = p.Execute(context.Background(), func(context.Context) error { return nil })
+	if len(trace) != 4 || trace[0] != "enter a" || trace[1] != "enter b" {
+		t.Fatalf("expected nested policy to run inside, got %v", trace)
+	}
+}
+
+func TestTimeoutSetsDeadline(t *testing.T) {
+	p := New(Timeout(time.Second))
+	err := p.Execute(context.Background(), func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		if !ok || time.Until(deadline) > time.Second {
+			t.Fatalf("expected a deadline within 1s, got %v (%v)", deadline, ok)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestFallbackReplacesError(t *testing.T) {
+	boom := errors.New("boom")
+	var got error
+	p := New(Fallback(func(_ context.Context, err error) error {
+		got = err
+		return nil
+	}))
+	if err := p.Execute(context.Background(), func(context.Context) error { return boom }); err != nil {
+		t.Fatalf("expected fallback to handle error, got %v", err)
+	}
+	if got != boom {
+		t.Fatalf("expected fallback to receive call error, got %v", got)
+	}
+}
+
+func TestStrategyConstructorsPanic(t *testing.T) {
+	for name, fn := range map[string]func(){
+		"timeout":  func() { _ = Timeout(0) },
+		"fallback": func() { _ = Fallback(nil) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("%s: expected panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+func TestExecuteFullChain(t *testing.T) {
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(2),
+		breaker.WithTimeout(time.Minute),
+	)
+	r := retry.New(retry.WithMaxAttempts(5), retry.WithBackoff(0, 0, 1))
+	bh := bulkhead.New("svc", bulkhead.WithMaxConcurrent(1))
+
+	var fallbackErr error
+	p := New(
+		Fallback(func(_ context.Context, err error) error {
+			fallbackErr = err
+			return nil
+		}),
+		Timeout(time.Second),
+		r,
+		cb,
+		bh,
+	)
+
+	attempts := 0
+	err := p.Execute(context.Background(), func(ctx context.Context) error {
+		attempts++
+		if _, ok := ctx.Deadline(); !ok {
+			t.Fatalf("expected the timeout to reach the call")
+		}
+		return errors.New("boom")
+	})
+	if err != nil {
+		t.Fatalf("expected fallback to handle error, got %v", err)
+	}
+	if !errors.Is(fallbackErr, breaker.ErrCircuitOpen) {
+		t.Fatalf("expected retries to end on the open circuit, got %v", fallbackErr)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts before the breaker opened, got %d", attempts)
+	}
+	if bh.InFlight() != 0 {
+		t.Fatalf("expected bulkhead slots to be released, got %d", bh.InFlight())
+	}
+}
+
+func TestBreakerIsStrategy(t *testing.T) {
+	cb := breaker.New("svc", breaker.WithFailureThreshold(1), breaker.WithTimeout(time.Minute))
+	p := New(cb)
+	_ = p.Execute(context.Background(), func(context.Context) error { return errors.New("boom") })
+	if err := p.Execute(context.Background(), func(context.Context) error { return nil }); !errors.Is(err, breaker.ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+}
+
+func TestNewAppliesCanonicalOrder(t *testing.T) {
+	cb := breaker.New("svc", breaker.WithTimeout(time.Minute))
+	r := retry.New(retry.WithMaxAttempts(1))
+	bh := bulkhead.New("svc")
+
+	fallbacks := 0
+	// The order from the request: the fallback still ends up outermost.
+	p := New(
+		Timeout(time.Second),
+		r,
+		cb,
+		bh,
+		Fallback(func(context.Context, error) error {
+			fallbacks++
+			return nil
+		}),
+	)
+	for range 5 {
+		if err := p.Execute(context.Background(), func(context.Context) error { return errors.New("boom") }); err != nil {
+			t.Fatalf("expected fallback to handle error, got %v", err)
+		}
+	}
+	if state, _ := cb.State(context.Background()); state != breaker.StateOpen {
+		t.Fatalf("expected the breaker to see the failures and open, got %v", state)
+	}
+	if fallbacks != 5 {
+		t.Fatalf("expected 5 fallbacks, got %d", fallbacks)
+	}
+}
+
+func TestErrorsDistinctFromOtherPackages(t *testing.T) {
+	for _, err := range []error{breaker.ErrInvalidDuration, bulkhead.ErrInvalidDuration, retry.ErrInvalidDuration} {
+		if errors.Is(ErrInvalidDuration, err) {
+			t.Fatalf("expected policy error to differ from %v", err)
+		}
+	}
+}
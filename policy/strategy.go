@@ -0,0 +1,39 @@
+package policy
+
+import (
+	"context"
+	"time"
+)
+
+// Timeout bounds everything inside it with a deadline of d.
+func Timeout(d time.Duration) Strategy {
+	if d <= 0 {
+		panic(ErrInvalidDuration)
+	}
+	return timeout(d)
+}
+
+type timeout time.Duration
+
+func (t timeout) ExecuteCtx(ctx context.Context, fn func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(t))
+	defer cancel()
+	return fn(ctx)
+}
+
+// Fallback returns the result of fn in place of any error from inside it.
+func Fallback(fn func(ctx context.Context, err error) error) Strategy {
+	if fn == nil {
+		panic(ErrNilFunction)
+	}
+	return fallback(fn)
+}
+
+type fallback func(ctx context.Context, err error) error
+
+func (f fallback) ExecuteCtx(ctx context.Context, fn func(context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		return f(ctx, err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package breaker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/shuklasaharsh/circuitbreaker/storage"
+)
+
+const defaultHealthCheckInterval = 5 * time.Second
+
+// prober tracks the background health check goroutine. At most one runs at a
+// time, and none start after Close.
+type prober struct {
+	mu      sync.Mutex
+	running bool
+	closed  bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func newProber() *prober {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &prober{ctx: ctx, cancel: cancel}
+}
+
+// Close stops the background health check, waiting for a check in progress to
+// return. The breaker keeps working, but no longer probes while open.
+func (b *Breaker) Close() error {
+	p := b.prober
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+	p.cancel()
+	p.wg.Wait()
+	return nil
+}
+
+// startProbe starts the health check goroutine unless it is already running.
+func (b *Breaker) startProbe() {
+	p := b.prober
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.running || p.closed {
+		return
+	}
+	p.running = true
+	p.wg.Add(1)
+	go b.probe(p.ctx)
+}
+
+// probe checks the dependency on every interval until the breaker leaves the
+// open state or is closed.
+func (b *Breaker) probe(ctx context.Context) {
+	p := b.prober
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(b.healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			p.mu.Lock()
+			p.running = false
+			p.mu.Unlock()
+			return
+		case <-ticker.C:
+		}
+		if b.probeOnce(ctx) {
+			continue
+		}
+		p.mu.Lock()
+		p.running = false
+		p.mu.Unlock()
+		// A trip that landed before running was cleared found the prober
+		// still running and did not start another one.
+		if state, err := b.State(ctx); err == nil && state == storage.StateOpen {
+			b.startProbe()
+		}
+		return
+	}
+}
+
+// probeOnce runs a single health check and reports whether the breaker is
+// still open. Store errors keep the prober running so it can try again.
+func (b *Breaker) probeOnce(ctx context.Context) bool {
+	state, err := b.State(ctx)
+	if err != nil {
+		return true
+	}
+	if state != storage.StateOpen {
+		return false
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, b.healthCheckInterval)
+	_, err = call(func() error {
+		return b.healthCheck.Check(checkCtx)
+	})
+	cancel()
+	if err != nil {
+		return true
+	}
+
+	record, err := b.update(ctx, func(record *storage.Record) {
+		if record.State != storage.StateOpen {
+			return
+		}
+		if b.healthCheckTransition == storage.StateClosed {
+			b.close(record, b.clock.Now())
+			return
+		}
+		b.halfOpen(record)
+	})
+	if err != nil {
+		return true
+	}
+	return record.State == storage.StateOpen
+}
@@ -0,0 +1,59 @@
+package breaker
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/shuklasaharsh/circuitbreaker/storage"
+)
+
+// RampCurve shapes how a recovery ramp lets traffic back in.
+type RampCurve uint8
+
+const (
+	// RampLinear admits a fraction of calls that grows evenly from 1% to 100%.
+	RampLinear RampCurve = iota
+	// RampExponential admits 1% of calls at first and multiplies the fraction
+	// at an even rate up to 100%, so a cold dependency warms up on light load.
+	RampExponential
+)
+
+func (c RampCurve) String() string {
+	switch c {
+	case RampLinear:
+		return "Linear"
+	case RampExponential:
+		return "Exponential"
+	default:
+		return "Unknown"
+	}
+}
+
+// rampFloor is the fraction of calls admitted when a ramp starts, so a breaker
+// that just closed never lets in less traffic than its half-open trials did.
+const rampFloor = 0.01
+
+// rampFraction returns the fraction of calls admitted elapsed into a ramp
+// lasting d.
+func rampFraction(curve RampCurve, elapsed, d time.Duration) float64 {
+	progress := min(1, max(0, float64(elapsed)/float64(d)))
+	if curve == RampExponential {
+		return math.Pow(1/rampFloor, progress) * rampFloor
+	}
+	return rampFloor + (1-rampFloor)*progress
+}
+
+// admitRamp decides whether a call may run while the closed record is still
+// ramping up. The ramp start is cleared once the ramp is over.
+func (b *Breaker) admitRamp(record *storage.Record, now time.Time) bool {
+	if b.recoveryRamp <= 0 || record.RampStart.IsZero() {
+		return true
+	}
+	elapsed := now.Sub(record.RampStart)
+	if elapsed >= b.recoveryRamp {
+		record.RampStart = time.Time{}
+		return true
+	}
+	return rand.Float64() < rampFraction(b.recoveryRampCurve, elapsed, b.recoveryRamp)
+}
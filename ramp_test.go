@@ -0,0 +1,138 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shuklasaharsh/circuitbreaker/breakertest"
+	"github.com/shuklasaharsh/circuitbreaker/storage"
+)
+
+func TestRampFraction(t *testing.T) {
+	d := 10 * time.Second
+	cases := []struct {
+		curve   RampCurve
+		elapsed time.Duration
+		want    float64
+	}{
+		{RampLinear, 0, 0.01},
+		{RampLinear, 5 * time.Second, 0.505},
+		{RampLinear, d, 1},
+		{RampExponential, 0, 0.01},
+		{RampExponential, 5 * time.Second, 0.1},
+		{RampExponential, d, 1},
+	}
+	for _, tc := range cases {
+		if got := rampFraction(tc.curve, tc.elapsed, d); math.Abs(got-tc.want) > 1e-9 {
+			t.Fatalf("%v at %v: expected %v, got %v", tc.curve, tc.elapsed, tc.want, got)
+		}
+	}
+}
+
+// recoverBreaker trips cb and closes it again through half-open.
+func recoverBreaker(t *testing.T, cb *Breaker, clock *breakertest.FakeClock) {
+	t.Helper()
+	_ = cb.Execute(func() error { return errors.New("boom") })
+	clock.Advance(time.Minute + time.Second)
+	for range 2 {
+		if err := cb.Execute(func() error { return nil }); err != nil {
+			t.Fatalf("trial call error: %v", err)
+		}
+	}
+}
+
+func TestRecoveryRampAdmitsTrafficGradually(t *testing.T) {
+	clock := breakertest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cb := New("svc",
+		WithFailureThreshold(1),
+		WithTimeout(time.Minute),
+		WithRecoveryRamp(10*time.Second),
+		WithClock(clock),
+	)
+	recoverBreaker(t, cb, clock)
+
+	record, err := cb.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if record.State != StateClosed || !record.RampStart.Equal(clock.Now()) {
+		t.Fatalf("expected closed record ramping from now, got %v %v", record.State, record.RampStart)
+	}
+
+	// A linear ramp starts at the 1% floor.
+	admitted := 0
+	for range 200 {
+		if cb.Execute(func() error { return nil }) == nil {
+			admitted++
+		}
+	}
+	if admitted > 20 {
+		t.Fatalf("expected about 1%% of calls admitted at ramp start, got %d of 200", admitted)
+	}
+
+	clock.Advance(5 * time.Second)
+	admitted = 0
+	for range 200 {
+		if cb.Execute(func() error { return nil }) == nil {
+			admitted++
+		}
+	}
+	if admitted < 50 || admitted > 150 {
+		t.Fatalf("expected about half the calls admitted mid-ramp, got %d of 200", admitted)
+	}
+
+	clock.Advance(5 * time.Second)
+	for i := range 50 {
+		if err := cb.Execute(func() error { return nil }); err != nil {
+			t.Fatalf("call %d rejected after ramp: %v", i, err)
+		}
+	}
+	record, _ = cb.Snapshot(context.Background())
+	if !record.RampStart.IsZero() {
+		t.Fatalf("expected ramp start cleared, got %v", record.RampStart)
+	}
+}
+
+func TestRecoveryRampSharedThroughStore(t *testing.T) {
+	clock := breakertest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	store := storage.NewMemoryStore()
+	opts := []Option{
+		WithFailureThreshold(1),
+		WithTimeout(time.Minute),
+		WithRecoveryRamp(10 * time.Second),
+		WithClock(clock),
+	}
+	first := NewDistributed("svc", store, opts...)
+	second := NewDistributed("svc", store, opts...)
+	recoverBreaker(t, first, clock)
+
+	rejected := 0
+	for range 100 {
+		if errors.Is(second.Execute(func() error { return nil }), ErrCircuitOpen) {
+			rejected++
+		}
+	}
+	if rejected < 80 {
+		t.Fatalf("expected the other instance to follow the ramp, got %d of 100 rejected", rejected)
+	}
+}
+
+func TestResetSkipsRecoveryRamp(t *testing.T) {
+	clock := breakertest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cb := New("svc",
+		WithFailureThreshold(1),
+		WithTimeout(time.Minute),
+		WithRecoveryRamp(10*time.Second),
+		WithClock(clock),
+	)
+	recoverBreaker(t, cb, clock)
+	if err := cb.Reset(context.Background()); err != nil {
+		t.Fatalf("reset error: %v", err)
+	}
+	if err := cb.Execute(func() error { return nil }); err != nil {
+		t.Fatalf("expected full traffic after reset, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package retry
+
+import (
+	"sync"
+	"time"
+
+	"github.com/shuklasaharsh/circuitbreaker/storage"
+)
+
+// budgetBuckets is the number of buckets a budget window is split into.
+const budgetBuckets = 10
+
+// Budget is a token bucket that caps retries at a share of successful calls
+// over a sliding window, so retries cannot multiply the load on a struggling
+// dependency. A Budget is safe for concurrent use and can be shared by
+// several Retriers.
+type Budget struct {
+	ratio      float64
+	minRetries int64
+	span       time.Duration
+	width      time.Duration
+	clock      storage.Clock
+
+	mu      sync.Mutex
+	buckets []budgetBucket
+}
+
+type budgetBucket struct {
+	start       time.Time
+	deposits    int64
+	withdrawals int64
+}
+
+// BudgetOption configures a Budget.
+type BudgetOption func(*Budget)
+
+// WithBudgetClock reads the time from clock instead of the wall clock, so
+// tests can move through the budget window without sleeping.
+func WithBudgetClock(clock storage.Clock) BudgetOption {
+	if clock == nil {
+		panic(ErrInvalidClock)
+	}
+	return func(b *Budget) {
+		b.clock = clock
+	}
+}
+
+// NewBudget allows ratio retries per successful call over the last window,
+// plus minRetries per window so a cold or idle dependency can still be
+// retried. NewBudget(0.2, 10, time.Minute) allows retries for 20% of calls.
+func NewBudget(ratio float64, minRetries int64, window time.Duration, opts ...BudgetOption) *Budget {
+	if ratio < 0 {
+		panic(ErrInvalidRatio)
+	}
+	if minRetries < 0 {
+		panic(ErrInvalidMinRetries)
+	}
+	if window < budgetBuckets {
+		panic(ErrInvalidDuration)
+	}
+	b := &Budget{
+		ratio:      ratio,
+		minRetries: minRetries,
+		span:       window,
+		width:      window / budgetBuckets,
+		clock:      storage.SystemClock{},
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Deposit records a successful call, earning ratio tokens.
+func (b *Budget) Deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current(b.clock.Now()).deposits++
+}
+
+// Withdraw takes a token for a retry and reports whether one was available.
+func (b *Budget) Withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.clock.Now()
+	if b.balance(now) < 1 {
+		return false
+	}
+	b.current(now).withdrawals++
+	return true
+}
+
+// Balance returns the number of retries the budget currently allows.
+func (b *Budget) Balance() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return int64(b.balance(b.clock.Now()))
+}
+
+func (b *Budget) balance(now time.Time) float64 {
+	var deposits, withdrawals int64
+	for _, bucket := range b.live(now) {
+		deposits += bucket.deposits
+		withdrawals += bucket.withdrawals
+	}
+	return float64(b.minRetries) + b.ratio*float64(deposits) - float64(withdrawals)
+}
+
+// current returns the bucket for now, dropping buckets that left the window.
+func (b *Budget) current(now time.Time) *budgetBucket {
+	b.buckets = b.live(now)
+	start := now.Truncate(b.width)
+	if n := len(b.buckets); n == 0 || !b.buckets[n-1].start.Equal(start) {
+		b.buckets = append(b.buckets, budgetBucket{start: start})
+	}
+	return &b.buckets[len(b.buckets)-1]
+}
+
+// live returns the buckets that still overlap the window ending at now.
+func (b *Budget) live(now time.Time) []budgetBucket {
+	cutoff := now.Truncate(b.width).Add(-b.span)
+	for i, bucket := range b.buckets {
+		if bucket.start.After(cutoff) {
+			return b.buckets[i:]
+		}
+	}
+	return nil
+}
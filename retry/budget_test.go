@@ -0,0 +1,133 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/shuklasaharsh/circuitbreaker/breakertest"
+	cberrors "github.com/shuklasaharsh/circuitbreaker/errors"
+)
+
+func TestBudgetAllowsRatioOfDeposits(t *testing.T) {
+	b := NewBudget(0.2, 0, time.Minute)
+	if b.Withdraw() {
+		t.Fatalf("expected an empty budget to refuse retries")
+	}
+	for range 10 {
+		b.Deposit()
+	}
+	if b.Balance() != 2 {
+		t.Fatalf("expected 2 retries for 10 successes, got %d", b.Balance())
+	}
+	if !b.Withdraw() || !b.Withdraw() {
+		t.Fatalf("expected 2 retries to be allowed")
+	}
+	if b.Withdraw() {
+		t.Fatalf("expected the third retry to be refused")
+	}
+}
+
+func TestBudgetMinRetries(t *testing.T) {
+	b := NewBudget(0, 1, time.Minute)
+	if !b.Withdraw() {
+		t.Fatalf("expected the reserve to allow a retry")
+	}
+	if b.Withdraw() {
+		t.Fatalf("expected the reserve to be spent")
+	}
+}
+
+func TestBudgetWindowExpires(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := NewBudget(1, 0, 10*time.Second)
+		b.Deposit()
+		if !b.Withdraw() {
+			t.Fatalf("expected a retry to be allowed")
+		}
+
+		time.Sleep(20 * time.Second)
+		if b.Withdraw() {
+			t.Fatalf("expected deposits to expire with the window")
+		}
+		b.Deposit()
+		if b.Balance() != 1 {
+			t.Fatalf("expected old withdrawals to expire too, got %d", b.Balance())
+		}
+	})
+}
+
+func TestBudgetSharedAcrossGoroutines(t *testing.T) {
+	b := NewBudget(0.5, 0, time.Minute)
+	for range 100 {
+		b.Deposit()
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for range 200 {
+		wg.Go(func() {
+			if b.Withdraw() {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	if allowed != 50 {
+		t.Fatalf("expected exactly 50 retries, got %d", allowed)
+	}
+}
+
+func TestNewBudgetPanics(t *testing.T) {
+	assertPanics(t, func() { _ = NewBudget(-0.1, 0, time.Minute) })
+	assertPanics(t, func() { _ = NewBudget(0.2, -1, time.Minute) })
+	assertPanics(t, func() { _ = NewBudget(0.2, 0, 0) })
+	assertPanics(t, func() { _ = WithBudgetClock(nil) })
+}
+
+func TestBudgetWithClock(t *testing.T) {
+	clock := breakertest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := NewBudget(1, 0, 10*time.Second, WithBudgetClock(clock))
+	b.Deposit()
+	if b.Balance() != 1 {
+		t.Fatalf("expected 1 retry, got %d", b.Balance())
+	}
+	clock.Advance(20 * time.Second)
+	if b.Balance() != 0 {
+		t.Fatalf("expected deposits to expire with the fake clock, got %d", b.Balance())
+	}
+}
+
+func TestExecuteStopsWhenBudgetExhausted(t *testing.T) {
+	budget := NewBudget(0, 1, time.Minute)
+	r := New(WithMaxAttempts(5), WithBackoff(0, 0, 1), WithBudget(budget))
+
+	boom := errors.New("boom")
+	attempts := 0
+	err := r.Execute(func() error {
+		attempts++
+		return boom
+	})
+	if !errors.Is(err, cberrors.ErrRetryBudgetExhausted) || !errors.Is(err, boom) {
+		t.Fatalf("expected ErrRetryBudgetExhausted joined with call error, got %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected the first attempt and one budgeted retry, got %d", attempts)
+	}
+}
+
+func TestExecuteDepositsOnSuccess(t *testing.T) {
+	budget := NewBudget(1, 0, time.Minute)
+	r := New(WithBudget(budget))
+	if err := r.Execute(func() error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if budget.Balance() != 1 {
+		t.Fatalf("expected a success to earn a retry, got %d", budget.Balance())
+	}
+}
@@ -0,0 +1,109 @@
+package retry
+
+import (
+	"time"
+
+	breaker "github.com/shuklasaharsh/circuitbreaker"
+)
+
+type Config struct {
+	MaxAttempts    int64
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Jitter         time.Duration
+	Retryable      func(error) bool
+	Breaker        *breaker.Breaker
+	CountRetries   bool
+	Budget         *Budget
+}
+
+type Option func(*Config)
+
+// WithMaxAttempts sets the total number of attempts, including the first.
+func WithMaxAttempts(n int64) Option {
+	if n <= 0 {
+		panic(ErrInvalidMaxAttempts)
+	}
+	return func(c *Config) {
+		c.MaxAttempts = n
+	}
+}
+
+// WithBackoff waits initial before the first retry and multiplies the wait by
+// multiplier for each one after, up to max.
+func WithBackoff(initial, max time.Duration, multiplier float64) Option {
+	if initial < 0 || max < initial {
+		panic(ErrInvalidDuration)
+	}
+	if multiplier < 1 {
+		panic(ErrInvalidMultiplier)
+	}
+	return func(c *Config) {
+		c.InitialBackoff = initial
+		c.MaxBackoff = max
+		c.Multiplier = multiplier
+	}
+}
+
+// WithJitter randomly extends each wait by up to d, so callers that failed
+// together do not retry together.
+func WithJitter(d time.Duration) Option {
+	if d < 0 {
+		panic(ErrInvalidDuration)
+	}
+	return func(c *Config) {
+		c.Jitter = d
+	}
+}
+
+// WithRetryable retries only errors for which fn returns true.
+func WithRetryable(fn func(error) bool) Option {
+	if fn == nil {
+		panic(ErrNilFunction)
+	}
+	return func(c *Config) {
+		c.Retryable = fn
+	}
+}
+
+// WithBreaker runs attempts through b. Every attempt must be admitted by b,
+// so retrying stops as soon as the circuit opens. Unless WithCountRetries is
+// set, only the final attempt is recorded by b.
+func WithBreaker(b *breaker.Breaker) Option {
+	if b == nil {
+		panic(ErrInvalidBreaker)
+	}
+	return func(c *Config) {
+		c.Breaker = b
+	}
+}
+
+// WithCountRetries records every attempt as a separate call of the breaker
+// set with WithBreaker, so retries count toward tripping it.
+func WithCountRetries() Option {
+	return func(c *Config) {
+		c.CountRetries = true
+	}
+}
+
+// WithBudget takes a token from budget for every retry and deposits into it
+// for every successful call. Without a token the retry is skipped and the call
+// fails with errors.ErrRetryBudgetExhausted joined with the last error.
+func WithBudget(budget *Budget) Option {
+	if budget == nil {
+		panic(ErrInvalidBudget)
+	}
+	return func(c *Config) {
+		c.Budget = budget
+	}
+}
+
+func defaultConfig() Config {
+	return Config{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+	}
+}
@@ -0,0 +1,67 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	breaker "github.com/shuklasaharsh/circuitbreaker"
+)
+
+func assertPanics(t *testing.T, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic")
+		}
+	}()
+	fn()
+}
+
+func TestDefaultConfig(t *testing.T) {
+	cfg := defaultConfig()
+	if cfg.MaxAttempts != 3 || cfg.InitialBackoff != 100*time.Millisecond || cfg.MaxBackoff != 10*time.Second || cfg.Multiplier != 2 {
+		t.Fatalf("unexpected defaults: %+v", cfg)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	cfg := defaultConfig()
+	cb := breaker.New("svc")
+	for _, opt := range []Option{
+		WithMaxAttempts(5),
+		WithBackoff(time.Second, time.Minute, 3),
+		WithJitter(time.Second),
+		WithRetryable(func(error) bool { return true }),
+		WithBreaker(cb),
+		WithCountRetries(),
+	} {
+		opt(&cfg)
+	}
+	if cfg.MaxAttempts != 5 || cfg.InitialBackoff != time.Second || cfg.MaxBackoff != time.Minute || cfg.Multiplier != 3 {
+		t.Fatalf("unexpected backoff config: %+v", cfg)
+	}
+	if cfg.Jitter != time.Second || cfg.Retryable == nil || cfg.Breaker != cb || !cfg.CountRetries {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+}
+
+func TestOptionsPanic(t *testing.T) {
+	assertPanics(t, func() { _ = WithMaxAttempts(0) })
+	assertPanics(t, func() { _ = WithBackoff(-time.Second, time.Second, 2) })
+	assertPanics(t, func() { _ = WithBackoff(time.Minute, time.Second, 2) })
+	assertPanics(t, func() { _ = WithBackoff(time.Second, time.Minute, 0.5) })
+	assertPanics(t, func() { _ = WithJitter(-time.Second) })
+	assertPanics(t, func() { _ = WithRetryable(nil) })
+	assertPanics(t, func() { _ = WithBreaker(nil) })
+	assertPanics(t, func() { _ = WithBudget(nil) })
+}
+
+func TestErrorsDistinctFromBreaker(t *testing.T) {
+	if errors.Is(ErrInvalidDuration, breaker.ErrInvalidDuration) {
+		t.Fatalf("expected retry and breaker errors to be distinct")
+	}
+	if errors.Is(ErrInvalidClock, breaker.ErrInvalidClock) {
+		t.Fatalf("expected retry and breaker clock errors to be distinct")
+	}
+}
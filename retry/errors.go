@@ -0,0 +1,15 @@
+package retry
+
+import "github.com/shuklasaharsh/circuitbreaker/errors"
+
+var (
+	ErrInvalidMaxAttempts = errors.NewError(100, "supplied max attempts is invalid", errors.ConfigError)
+	ErrInvalidDuration    = errors.NewError(101, "supplied retry duration is invalid", errors.ConfigError)
+	ErrInvalidMultiplier  = errors.NewError(102, "supplied multiplier is invalid", errors.ConfigError)
+	ErrNilFunction        = errors.NewError(103, "function cannot be nil", errors.ConfigError)
+	ErrInvalidBreaker     = errors.NewError(104, "breaker cannot be nil", errors.ConfigError)
+	ErrInvalidRatio       = errors.NewError(105, "supplied budget ratio is invalid", errors.ConfigError)
+	ErrInvalidMinRetries  = errors.NewError(106, "supplied minimum retries is invalid", errors.ConfigError)
+	ErrInvalidBudget      = errors.NewError(107, "retry budget cannot be nil", errors.ConfigError)
+	ErrInvalidClock       = errors.NewError(108, "budget clock cannot be nil", errors.ConfigError)
+)
@@ -0,0 +1,188 @@
+// Package retry retries failed calls with exponential backoff, and stops as
+// soon as a circuit breaker rejects a call.
+package retry
+
+import (
+	"context"
+	stderrors "errors"
+	"math/rand/v2"
+	"runtime/debug"
+	"time"
+
+	breaker "github.com/shuklasaharsh/circuitbreaker"
+	"github.com/shuklasaharsh/circuitbreaker/errors"
+)
+
+type Retrier struct {
+	maxAttempts    int64
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	multiplier     float64
+	jitter         time.Duration
+	retryable      func(error) bool
+	breaker        *breaker.Breaker
+	countRetries   bool
+	budget         *Budget
+}
+
+func New(opts ...Option) *Retrier {
+	cfg := defaultConfig()
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &Retrier{
+		maxAttempts:    cfg.MaxAttempts,
+		initialBackoff: cfg.InitialBackoff,
+		maxBackoff:     cfg.MaxBackoff,
+		multiplier:     cfg.Multiplier,
+		jitter:         cfg.Jitter,
+		retryable:      cfg.Retryable,
+		breaker:        cfg.Breaker,
+		countRetries:   cfg.CountRetries,
+		budget:         cfg.Budget,
+	}
+}
+
+// Execute runs the given function, retrying it on failure.
+func (r *Retrier) Execute(fn func() error) error {
+	return r.ExecuteContext(context.Background(), fn)
+}
+
+// ExecuteContext runs the given function, retrying it on failure until it
+// succeeds, the attempts run out or ctx is done.
+func (r *Retrier) ExecuteContext(ctx context.Context, fn func() error) error {
+	if fn == nil {
+		return ErrNilFunction
+	}
+	return r.ExecuteCtx(ctx, func(context.Context) error {
+		return fn()
+	})
+}
+
+// ExecuteCtx runs the given function, retrying it on failure, and passes it
+// the context it should observe. It returns the error of the last attempt.
+func (r *Retrier) ExecuteCtx(ctx context.Context, fn func(context.Context) error) error {
+	if fn == nil {
+		return ErrNilFunction
+	}
+	switch {
+	case r.breaker == nil:
+		return r.retry(ctx, fn, nil)
+	case r.countRetries:
+		return r.retry(ctx, func(ctx context.Context) error {
+			return r.breaker.ExecuteCtx(ctx, fn)
+		}, nil)
+	default:
+		return r.retry(ctx, fn, r.breaker)
+	}
+}
+
+// retry calls fn until it succeeds or a stop condition is met. With gate set,
+// every attempt must be admitted by it, so retrying stops as soon as the
+// circuit opens, but only the outcome of the last attempt is recorded.
+func (r *Retrier) retry(ctx context.Context, fn func(context.Context) error, gate *breaker.Breaker) error {
+	var err error
+	for attempt := int64(1); ; attempt++ {
+		var permit breaker.Permit
+		if gate != nil {
+			var allowErr error
+			permit, allowErr = gate.Allow(ctx)
+			if allowErr != nil {
+				if err != nil {
+					return stderrors.Join(err, allowErr)
+				}
+				return allowErr
+			}
+			err = r.attempt(ctx, gate, permit, fn)
+		} else {
+			err = fn(ctx)
+		}
+
+		stop := err == nil || attempt >= r.maxAttempts || !r.shouldRetry(ctx, err)
+		if !stop && r.budget != nil && !r.budget.Withdraw() {
+			err = stderrors.Join(err, errors.ErrRetryBudgetExhausted)
+			stop = true
+		}
+		if gate != nil {
+			if recordErr := r.record(ctx, permit, err, stop); recordErr != nil {
+				return stderrors.Join(err, recordErr)
+			}
+		}
+		if err == nil {
+			if r.budget != nil {
+				r.budget.Deposit()
+			}
+			return nil
+		}
+		if stop {
+			return err
+		}
+
+		timer := time.NewTimer(r.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return stderrors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt runs fn once for a call admitted by gate. A panic is recorded as a
+// failure and raised again, or returned as a *breaker.PanicError under
+// breaker.PanicReturnError.
+func (r *Retrier) attempt(ctx context.Context, gate *breaker.Breaker, permit breaker.Permit, fn func(context.Context) error) (err error) {
+	callCtx, cancel := gate.CallContext(ctx)
+	defer cancel()
+	defer func() {
+		if v := recover(); v != nil {
+			panicErr := &breaker.PanicError{Value: v, Stack: debug.Stack()}
+			if gate.PanicPolicy() == breaker.PanicRepanic {
+				_ = permit.Failure(panicErr)
+				panic(v)
+			}
+			err = panicErr
+		}
+	}()
+	return fn(callCtx)
+}
+
+// record reports an attempt to the breaker. Attempts that will be retried are
+// not recorded, nor are calls abandoned because ctx was cancelled.
+func (r *Retrier) record(ctx context.Context, permit breaker.Permit, err error, last bool) error {
+	switch {
+	case !last || stderrors.Is(ctx.Err(), context.Canceled):
+		return permit.Cancel()
+	case err == nil:
+		return permit.Success()
+	default:
+		return permit.Failure(err)
+	}
+}
+
+// shouldRetry reports whether err is worth another attempt. A rejection by a
+// breaker is not: the circuit stays open for longer than any backoff.
+func (r *Retrier) shouldRetry(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || stderrors.Is(err, breaker.ErrCircuitOpen) {
+		return false
+	}
+	if r.retryable != nil {
+		return r.retryable(err)
+	}
+	return true
+}
+
+// backoff returns the wait after the given attempt.
+func (r *Retrier) backoff(attempt int64) time.Duration {
+	wait := r.initialBackoff
+	for i := int64(1); i < attempt && wait < r.maxBackoff; i++ {
+		wait = time.Duration(float64(wait) * r.multiplier)
+	}
+	wait = min(wait, r.maxBackoff)
+	if r.jitter > 0 {
+		wait += time.Duration(rand.Int64N(int64(r.jitter)))
+	}
+	return wait
+}
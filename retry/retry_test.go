@@ -0,0 +1,253 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	breaker "github.com/shuklasaharsh/circuitbreaker"
+)
+
+func TestExecuteNilFunction(t *testing.T) {
+	r := New()
+	if err := r.Execute(nil); !errors.Is(err, ErrNilFunction) {
+		t.Fatalf("expected ErrNilFunction, got %v", err)
+	}
+	if err := r.ExecuteCtx(context.Background(), nil); !errors.Is(err, ErrNilFunction) {
+		t.Fatalf("expected ErrNilFunction, got %v", err)
+	}
+}
+
+func TestExecuteRetriesUntilSuccess(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		r := New(WithMaxAttempts(5), WithBackoff(time.Second, time.Minute, 2))
+		attempts := 0
+		start := time.Now()
+		err := r.Execute(func() error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("boom")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if attempts != 3 {
+			t.Fatalf("expected 3 attempts, got %d", attempts)
+		}
+		if waited := time.Since(start); waited != 3*time.Second {
+			t.Fatalf("expected 1s then 2s of backoff, waited %v", waited)
+		}
+	})
+}
+
+func TestExecuteReturnsLastErrorAfterMaxAttempts(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		r := New(WithMaxAttempts(3))
+		attempts := 0
+		last := errors.New("last")
+		err := r.Execute(func() error {
+			attempts++
+			if attempts == 3 {
+				return last
+			}
+			return errors.New("boom")
+		})
+		if err != last {
+			t.Fatalf("expected last error, got %v", err)
+		}
+		if attempts != 3 {
+			t.Fatalf("expected 3 attempts, got %d", attempts)
+		}
+	})
+}
+
+func TestExecuteStopsOnNonRetryableError(t *testing.T) {
+	permanent := errors.New("bad request")
+	r := New(WithRetryable(func(err error) bool { return !errors.Is(err, permanent) }))
+	attempts := 0
+	err := r.Execute(func() error {
+		attempts++
+		return permanent
+	})
+	if err != permanent || attempts != 1 {
+		t.Fatalf("expected one attempt with permanent error, got %d (%v)", attempts, err)
+	}
+}
+
+func TestExecuteStopsOnContextDone(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		r := New(WithMaxAttempts(10), WithBackoff(time.Second, time.Second, 1))
+		ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+		defer cancel()
+
+		boom := errors.New("boom")
+		attempts := 0
+		err := r.ExecuteContext(ctx, func() error {
+			attempts++
+			return boom
+		})
+		if !errors.Is(err, boom) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected call error joined with DeadlineExceeded, got %v", err)
+		}
+		if attempts != 2 {
+			t.Fatalf("expected 2 attempts before the deadline, got %d", attempts)
+		}
+	})
+}
+
+func TestBackoffCapsAndJitters(t *testing.T) {
+	r := New(WithBackoff(time.Second, 5*time.Second, 2))
+	for attempt, want := range map[int64]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if got := r.backoff(attempt); got != want {
+			t.Fatalf("attempt %d: expected %v, got %v", attempt, want, got)
+		}
+	}
+
+	r = New(WithBackoff(time.Second, time.Second, 1), WithJitter(time.Second))
+	for range 100 {
+		if got := r.backoff(1); got < time.Second || got >= 2*time.Second {
+			t.Fatalf("expected jittered backoff in [1s, 2s), got %v", got)
+		}
+	}
+}
+
+func TestExecuteStopsOnCircuitOpen(t *testing.T) {
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(2),
+		breaker.WithTimeout(time.Minute),
+	)
+	r := New(WithMaxAttempts(5), WithBackoff(0, 0, 1), WithBreaker(cb), WithCountRetries())
+
+	attempts := 0
+	err := r.Execute(func() error {
+		attempts++
+		return errors.New("boom")
+	})
+	if !errors.Is(err, breaker.ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected retries to stop once the breaker opened, got %d attempts", attempts)
+	}
+}
+
+func TestExecuteCountsRetriesOnce(t *testing.T) {
+	cb := breaker.New("svc", breaker.WithFailureThreshold(2))
+	r := New(WithMaxAttempts(3), WithBackoff(0, 0, 1), WithBreaker(cb))
+
+	attempts := 0
+	boom := errors.New("boom")
+	if err := r.Execute(func() error {
+		attempts++
+		return boom
+	}); !errors.Is(err, boom) {
+		t.Fatalf("expected call error, got %v", err)
+	}
+
+	record, err := cb.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if attempts != 3 || record.State != breaker.StateClosed || record.Failures != 1 {
+		t.Fatalf("expected 3 attempts recorded as 1 failure, got %d attempts, %v with %d failures", attempts, record.State, record.Failures)
+	}
+}
+
+func TestExecuteWithOpenBreakerSkipsAttempts(t *testing.T) {
+	cb := breaker.New("svc", breaker.WithFailureThreshold(1), breaker.WithTimeout(time.Minute))
+	_ = cb.Execute(func() error { return errors.New("boom") })
+
+	r := New(WithBreaker(cb))
+	called := false
+	err := r.Execute(func() error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, breaker.ErrCircuitOpen) || called {
+		t.Fatalf("expected rejection without attempts, got called=%v (%v)", called, err)
+	}
+}
+
+func TestExecuteStopsWhenBreakerOpensBetweenAttempts(t *testing.T) {
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithTimeout(time.Minute),
+	)
+	r := New(WithMaxAttempts(5), WithBackoff(0, 0, 1), WithBreaker(cb))
+
+	attempts := 0
+	boom := errors.New("boom")
+	err := r.Execute(func() error {
+		attempts++
+		// Another caller trips the breaker while this attempt runs.
+		_ = cb.Execute(func() error { return boom })
+		return boom
+	})
+	if !errors.Is(err, breaker.ErrCircuitOpen) || !errors.Is(err, boom) {
+		t.Fatalf("expected the last error joined with ErrCircuitOpen, got %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected no attempts once the breaker opened, got %d", attempts)
+	}
+}
+
+func TestExecuteAppliesCallTimeoutPerAttempt(t *testing.T) {
+	cb := breaker.New("svc", breaker.WithCallTimeout(time.Minute))
+	r := New(WithMaxAttempts(2), WithBackoff(0, 0, 1), WithBreaker(cb))
+
+	deadlines := 0
+	_ = r.ExecuteCtx(context.Background(), func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); ok {
+			deadlines++
+		}
+		return errors.New("boom")
+	})
+	if deadlines != 2 {
+		t.Fatalf("expected every attempt to get the call deadline, got %d", deadlines)
+	}
+}
+
+func TestExecuteHonoursPanicReturnError(t *testing.T) {
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithPanicPolicy(breaker.PanicReturnError),
+	)
+	r := New(WithMaxAttempts(2), WithBackoff(0, 0, 1), WithBreaker(cb))
+
+	attempts := 0
+	err := r.Execute(func() error {
+		attempts++
+		panic("boom")
+	})
+	var panicErr *breaker.PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "boom" {
+		t.Fatalf("expected *PanicError, got %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected the panic to be retried like an error, got %d attempts", attempts)
+	}
+	if state, _ := cb.State(context.Background()); state != breaker.StateOpen {
+		t.Fatalf("expected the panic to be recorded as a failure, got %v", state)
+	}
+}
+
+func TestExecuteRepanicsByDefault(t *testing.T) {
+	cb := breaker.New("svc", breaker.WithFailureThreshold(1))
+	r := New(WithBreaker(cb))
+
+	func() {
+		defer func() {
+			if v := recover(); v != "boom" {
+				t.Fatalf("expected original panic value, got %v", v)
+			}
+		}()
+		_ = r.Execute(func() error { panic("boom") })
+	}()
+	if state, _ := cb.State(context.Background()); state != breaker.StateOpen {
+		t.Fatalf("expected the panic to be recorded as a failure, got %v", state)
+	}
+}
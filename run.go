@@ -0,0 +1,46 @@
+package breaker
+
+import "context"
+
+// Run executes fn through the breaker and returns its result. It keeps the
+// semantics of ExecuteCtx: ErrCircuitOpen when the call is rejected, and
+// the call error joined with any error recording the outcome. A fallback set
+// with WithFallback is not used, since it cannot produce a T.
+func Run[T any](ctx context.Context, b *Breaker, fn func(context.Context) (T, error)) (T, error) {
+	var result T
+	if b == nil {
+		return result, ErrNilBreaker
+	}
+	if fn == nil {
+		return result, ErrNilFunction
+	}
+
+	err := b.execute(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+		return err
+	}, nil)
+	return result, err
+}
+
+// RunWithFallback is like Run, but returns the result of fallback when the call
+// is rejected or fails.
+func RunWithFallback[T any](ctx context.Context, b *Breaker, fn func(context.Context) (T, error), fallback func(context.Context, error) (T, error)) (T, error) {
+	var result T
+	if b == nil {
+		return result, ErrNilBreaker
+	}
+	if fn == nil || fallback == nil {
+		return result, ErrNilFunction
+	}
+
+	err := b.execute(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+		return err
+	}, func(ctx context.Context, err error) error {
+		result, err = fallback(ctx, err)
+		return err
+	})
+	return result, err
+}
@@ -0,0 +1,124 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunReturnsResult(t *testing.T) {
+	b := New("svc")
+	got, err := Run(context.Background(), b, func(context.Context) (int, error) {
+		return 42, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 42 {
+		t.Fatalf("expected 42, got %d", got)
+	}
+}
+
+func TestRunPassesContext(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+	got, err := Run(ctx, New("svc"), func(ctx context.Context) (string, error) {
+		return ctx.Value(key{}).(string), nil
+	})
+	if err != nil || got != "value" {
+		t.Fatalf("expected context value, got %q (%v)", got, err)
+	}
+}
+
+func TestRunNilArguments(t *testing.T) {
+	if _, err := Run[int](context.Background(), nil, func(context.Context) (int, error) { return 0, nil }); !errors.Is(err, ErrNilBreaker) {
+		t.Fatalf("expected ErrNilBreaker, got %v", err)
+	}
+	if _, err := Run[int](context.Background(), New("svc"), nil); !errors.Is(err, ErrNilFunction) {
+		t.Fatalf("expected ErrNilFunction, got %v", err)
+	}
+}
+
+func TestRunOpenCircuit(t *testing.T) {
+	b := New("svc",
+		WithFailureThreshold(1),
+		WithTimeout(time.Minute),
+	)
+	_ = b.Execute(func() error { return errors.New("boom") })
+
+	called := false
+	got, err := Run(context.Background(), b, func(context.Context) (string, error) {
+		called = true
+		return "value", nil
+	})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if called || got != "" {
+		t.Fatalf("expected rejected call, got called=%v result=%q", called, got)
+	}
+}
+
+func TestRunJoinsUpdateError(t *testing.T) {
+	updateErr := errors.New("update failed")
+	b := New("svc", WithStorage(&updateErrorStore{err: updateErr}))
+	callErr := errors.New("handler failed")
+
+	_, err := Run(context.Background(), b, func(context.Context) (int, error) {
+		return 0, callErr
+	})
+	if !errors.Is(err, callErr) || !errors.Is(err, updateErr) {
+		t.Fatalf("expected joined errors, got %v", err)
+	}
+}
+
+func TestRunWithFallbackOnOpenCircuit(t *testing.T) {
+	b := New("svc",
+		WithFailureThreshold(1),
+		WithTimeout(time.Minute),
+	)
+	_ = b.Execute(func() error { return errors.New("boom") })
+
+	got, err := RunWithFallback(context.Background(), b,
+		func(context.Context) (string, error) { return "live", nil },
+		func(_ context.Context, err error) (string, error) {
+			if !errors.Is(err, ErrCircuitOpen) {
+				t.Fatalf("expected ErrCircuitOpen in fallback, got %v", err)
+			}
+			return "cached", nil
+		},
+	)
+	if err != nil || got != "cached" {
+		t.Fatalf("expected cached fallback, got %q (%v)", got, err)
+	}
+}
+
+func TestRunWithFallbackOnFailure(t *testing.T) {
+	callErr := errors.New("boom")
+	got, err := RunWithFallback(context.Background(), New("svc"),
+		func(context.Context) (int, error) { return 0, callErr },
+		func(_ context.Context, err error) (int, error) { return -1, err },
+	)
+	if !errors.Is(err, callErr) || got != -1 {
+		t.Fatalf("expected fallback result with call error, got %d (%v)", got, err)
+	}
+}
+
+func TestRunWithFallbackNilFallback(t *testing.T) {
+	_, err := RunWithFallback(context.Background(), New("svc"),
+		func(context.Context) (int, error) { return 1, nil },
+		nil,
+	)
+	if !errors.Is(err, ErrNilFunction) {
+		t.Fatalf("expected ErrNilFunction, got %v", err)
+	}
+}
+
+func TestRunIgnoresBreakerFallback(t *testing.T) {
+	callErr := errors.New("boom")
+	b := New("svc", WithFallback(func(context.Context, error) error { return nil }))
+	if _, err := Run(context.Background(), b, func(context.Context) (int, error) { return 0, callErr }); !errors.Is(err, callErr) {
+		t.Fatalf("expected call error, got %v", err)
+	}
+}
@@ -8,4 +8,8 @@ const (
 	StateClosed   = storage.StateClosed
 	StateOpen     = storage.StateOpen
 	StateHalfOpen = storage.StateHalfOpen
+
+	StateForcedOpen   = storage.StateForcedOpen
+	StateForcedClosed = storage.StateForcedClosed
+	StateDisabled     = storage.StateDisabled
 )
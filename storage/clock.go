@@ -0,0 +1,16 @@
+package storage
+
+import "time"
+
+// Clock tells the time. Breakers and stores read it instead of calling
+// time.Now so tests can control time.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock reads the wall clock.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
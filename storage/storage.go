@@ -13,6 +13,12 @@ const (
 	StateClosed State = iota
 	StateOpen
 	StateHalfOpen
+	// StateForcedOpen rejects every call until the override is cleared.
+	StateForcedOpen
+	// StateForcedClosed admits every call and records outcomes without tripping.
+	StateForcedClosed
+	// StateDisabled admits every call without recording anything.
+	StateDisabled
 )
 
 func (s State) String() string {
@@ -23,16 +29,50 @@ func (s State) String() string {
 		return "Open"
 	case StateHalfOpen:
 		return "Half Open"
+	case StateForcedOpen:
+		return "Forced Open"
+	case StateForcedClosed:
+		return "Forced Closed"
+	case StateDisabled:
+		return "Disabled"
 	default:
 		return "Unknown"
 	}
 }
 
+// CallResult is the outcome of a single call kept in a count-based window.
+// CallFailure and CallSlow are flags and may be combined.
+type CallResult uint8
+
+const (
+	CallSuccess CallResult = 0
+	CallFailure CallResult = 1 << 0
+	CallSlow    CallResult = 1 << 1
+)
+
+// Bucket aggregates the calls made during one slice of a time-based window.
+type Bucket struct {
+	Start     time.Time `json:"start"`
+	Calls     int64     `json:"calls"`
+	Failures  int64     `json:"failures"`
+	SlowCalls int64     `json:"slow_calls"`
+	// Rejected counts calls turned away by adaptive throttling.
+	Rejected int64 `json:"rejected,omitempty"`
+}
+
 type Record struct {
-	State           State     `json:"state"`
-	Failures        int64     `json:"failures"`
-	Successes       int64     `json:"successes"`
-	LastFailureTime time.Time `json:"last_failure_time"`
+	State           State        `json:"state"`
+	Failures        int64        `json:"failures"`
+	Successes       int64        `json:"successes"`
+	SlowCalls       int64        `json:"slow_calls"`
+	HalfOpenCalls   int64        `json:"half_open_calls"`
+	HalfOpenLease   time.Time    `json:"half_open_lease"`
+	Trips           int64        `json:"trips"`
+	OpenUntil       time.Time    `json:"open_until"`
+	LastFailureTime time.Time    `json:"last_failure_time"`
+	RampStart       time.Time    `json:"ramp_start"`
+	Calls           []CallResult `json:"calls,omitempty"`
+	Buckets         []Bucket     `json:"buckets,omitempty"`
 }
 
 func DefaultRecord() Record {
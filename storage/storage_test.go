@@ -14,6 +14,9 @@ func TestStateString(t *testing.T) {
 		{StateClosed, "Closed"},
 		{StateOpen, "Open"},
 		{StateHalfOpen, "Half Open"},
+		{StateForcedOpen, "Forced Open"},
+		{StateForcedClosed, "Forced Closed"},
+		{StateDisabled, "Disabled"},
 		{State(99), "Unknown"},
 	}
 
@@ -73,3 +76,51 @@ func TestJSONCodecInvalidData(t *testing.T) {
 		t.Fatalf("expected non-ErrNotFound error, got %v", err)
 	}
 }
+
+func TestJSONCodecRoundTripCalls(t *testing.T) {
+	codec := JSONCodec{}
+	record := Record{
+		State: StateClosed,
+		Calls: []CallResult{CallSuccess, CallFailure, CallFailure},
+	}
+	data, err := codec.Marshal(record)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	decoded, err := codec.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if len(decoded.Calls) != len(record.Calls) {
+		t.Fatalf("expected %d calls, got %d", len(record.Calls), len(decoded.Calls))
+	}
+	for i := range record.Calls {
+		if decoded.Calls[i] != record.Calls[i] {
+			t.Fatalf("call %d: expected %v, got %v", i, record.Calls[i], decoded.Calls[i])
+		}
+	}
+}
+
+func TestJSONCodecRoundTripBuckets(t *testing.T) {
+	codec := JSONCodec{}
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	record := Record{
+		State:   StateClosed,
+		Buckets: []Bucket{{Start: start, Calls: 4, Failures: 1}},
+	}
+	data, err := codec.Marshal(record)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	decoded, err := codec.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if len(decoded.Buckets) != 1 {
+		t.Fatalf("expected 1 bucket, got %d", len(decoded.Buckets))
+	}
+	bucket := decoded.Buckets[0]
+	if !bucket.Start.Equal(start) || bucket.Calls != 4 || bucket.Failures != 1 {
+		t.Fatalf("decoded bucket mismatch: %#v", bucket)
+	}
+}
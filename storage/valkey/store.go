@@ -26,6 +26,7 @@ type Store struct {
 	ttl        time.Duration
 	codec      storage.Codec
 	maxRetries int
+	clock      storage.Clock
 }
 
 type Option func(*Store)
@@ -59,6 +60,16 @@ func WithMaxRetries(n int) Option {
 	}
 }
 
+// WithClock sets the clock used to keep open records alive until their open
+// period ends.
+func WithClock(clock storage.Clock) Option {
+	return func(s *Store) {
+		if clock != nil {
+			s.clock = clock
+		}
+	}
+}
+
 func New(client Client, opts ...Option) (*Store, error) {
 	if client == nil {
 		return nil, stderrors.New("valkey client cannot be nil")
@@ -70,6 +81,7 @@ func New(client Client, opts ...Option) (*Store, error) {
 		ttl:        0,
 		codec:      storage.JSONCodec{},
 		maxRetries: 3,
+		clock:      storage.SystemClock{},
 	}
 
 	for _, opt := range opts {
@@ -99,7 +111,7 @@ func (s *Store) Save(ctx context.Context, name string, record storage.Record) er
 	if err != nil {
 		return err
 	}
-	return s.client.Set(ctx, s.key(name), string(payload), s.ttl)
+	return s.client.Set(ctx, s.key(name), string(payload), s.ttlFor(record))
 }
 
 func (s *Store) Update(ctx context.Context, name string, fn func(storage.Record) (storage.Record, error)) (storage.Record, error) {
@@ -120,7 +132,7 @@ func (s *Store) Update(ctx context.Context, name string, fn func(storage.Record)
 			if err != nil {
 				return err
 			}
-			return tx.Set(ctx, s.key(name), string(payload), s.ttl)
+			return tx.Set(ctx, s.key(name), string(payload), s.ttlFor(updated))
 		})
 		if err == nil {
 			return updated, nil
@@ -156,6 +168,23 @@ func (s *Store) loadFromTx(ctx context.Context, tx Tx, name string) (storage.Rec
 	return record, nil
 }
 
+// ttlFor extends the TTL to cover the rest of an open period, so an expiring
+// key cannot close the circuit early. Overrides never expire: they hold until
+// they are cleared.
+func (s *Store) ttlFor(record storage.Record) time.Duration {
+	if s.ttl <= 0 {
+		return s.ttl
+	}
+	switch record.State {
+	case storage.StateForcedOpen, storage.StateForcedClosed, storage.StateDisabled:
+		return 0
+	}
+	if remaining := record.OpenUntil.Sub(s.clock.Now()); remaining > s.ttl {
+		return remaining
+	}
+	return s.ttl
+}
+
 func (s *Store) key(name string) string {
 	if s.keyPrefix == "" {
 		return name
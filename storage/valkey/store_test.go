@@ -7,12 +7,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/shuklasaharsh/circuitbreaker/breakertest"
 	"github.com/shuklasaharsh/circuitbreaker/storage"
 )
 
 type mockClient struct {
 	mu        sync.Mutex
 	data      map[string]string
+	ttls      map[string]time.Duration
 	conflicts int
 }
 
@@ -21,7 +23,7 @@ type mockTx struct {
 }
 
 func newMockClient() *mockClient {
-	return &mockClient{data: make(map[string]string)}
+	return &mockClient{data: make(map[string]string), ttls: make(map[string]time.Duration)}
 }
 
 func (m *mockClient) Get(_ context.Context, key string) (string, error) {
@@ -34,9 +36,10 @@ func (m *mockClient) Get(_ context.Context, key string) (string, error) {
 	return value, nil
 }
 
-func (m *mockClient) Set(_ context.Context, key, value string, _ time.Duration) error {
+func (m *mockClient) Set(_ context.Context, key, value string, ttl time.Duration) error {
 	m.mu.Lock()
 	m.data[key] = value
+	m.ttls[key] = ttl
 	m.mu.Unlock()
 	return nil
 }
@@ -93,6 +96,7 @@ func TestNewOptions(t *testing.T) {
 		WithTTL(2*time.Minute),
 		WithMaxRetries(5),
 		WithCodec(noopCodec{}),
+		WithClock(breakertest.NewFakeClock(time.Time{})),
 	)
 	if err != nil {
 		t.Fatalf("new error: %v", err)
@@ -109,6 +113,9 @@ func TestNewOptions(t *testing.T) {
 	if _, ok := store.codec.(noopCodec); !ok {
 		t.Fatalf("expected noopCodec, got %T", store.codec)
 	}
+	if _, ok := store.clock.(*breakertest.FakeClock); !ok {
+		t.Fatalf("expected fake clock, got %T", store.clock)
+	}
 }
 
 func TestWithMaxRetriesNegative(t *testing.T) {
@@ -233,3 +240,70 @@ func TestKeyPrefix(t *testing.T) {
 		t.Fatalf("unexpected key: %s", store.key("svc"))
 	}
 }
+
+func TestUpdatePersistsCalls(t *testing.T) {
+	store, err := New(newMockClient())
+	if err != nil {
+		t.Fatalf("new error: %v", err)
+	}
+	for _, result := range []storage.CallResult{storage.CallFailure, storage.CallSuccess} {
+		_, err := store.Update(context.Background(), "svc", func(r storage.Record) (storage.Record, error) {
+			r.Calls = append(r.Calls, result)
+			return r, nil
+		})
+		if err != nil {
+			t.Fatalf("update error: %v", err)
+		}
+	}
+	loaded, err := store.Load(context.Background(), "svc")
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	if len(loaded.Calls) != 2 || loaded.Calls[0] != storage.CallFailure || loaded.Calls[1] != storage.CallSuccess {
+		t.Fatalf("unexpected calls: %v", loaded.Calls)
+	}
+}
+
+func TestTTLCoversOpenPeriod(t *testing.T) {
+	clock := breakertest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	client := newMockClient()
+	store, _ := New(client, WithTTL(time.Minute), WithClock(clock))
+
+	record := storage.DefaultRecord()
+	record.State = storage.StateOpen
+	record.OpenUntil = clock.Now().Add(10 * time.Minute)
+	if err := store.Save(context.Background(), "svc", record); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+	if ttl := client.ttls["svc"]; ttl != 10*time.Minute {
+		t.Fatalf("expected ttl to cover open period, got %v", ttl)
+	}
+
+	clock.Advance(10 * time.Minute)
+	_, err := store.Update(context.Background(), "svc", func(r storage.Record) (storage.Record, error) {
+		return r, nil
+	})
+	if err != nil {
+		t.Fatalf("update error: %v", err)
+	}
+	if ttl := client.ttls["svc"]; ttl != time.Minute {
+		t.Fatalf("expected configured ttl once open period ends, got %v", ttl)
+	}
+}
+
+func TestTTLSkippedForOverrides(t *testing.T) {
+	client := newMockClient()
+	store, _ := New(client, WithTTL(time.Minute))
+
+	for _, state := range []storage.State{storage.StateForcedOpen, storage.StateForcedClosed, storage.StateDisabled} {
+		client.ttls["svc"] = time.Hour
+		record := storage.DefaultRecord()
+		record.State = state
+		if err := store.Save(context.Background(), "svc", record); err != nil {
+			t.Fatalf("save error: %v", err)
+		}
+		if ttl := client.ttls["svc"]; ttl != 0 {
+			t.Fatalf("expected %v record to never expire, got ttl %v", state, ttl)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package breaker
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/shuklasaharsh/circuitbreaker/storage"
+)
+
+// throttleBuckets is the number of buckets an adaptive throttling window is
+// split into.
+const throttleBuckets = 10
+
+// throttle rejects calls client-side with a probability that grows as the
+// backend accepts fewer of the calls made to it, instead of tripping open.
+type throttle struct {
+	k      float64
+	window timeWindow
+}
+
+func newThrottle(k float64, span time.Duration) *throttle {
+	return &throttle{k: k, window: newTimeWindow(span, throttleBuckets)}
+}
+
+// rejectProbability returns max(0, (requests - k*accepts) / (requests + 1)),
+// where requests counts every call made during the window, rejected ones
+// included, and accepts counts the calls that succeeded.
+func (t *throttle) rejectProbability(record storage.Record, now time.Time) float64 {
+	stats := t.window.stats(record, now)
+	requests := float64(stats.calls + stats.rejected)
+	accepts := float64(stats.calls - stats.failures)
+	return max(0, (requests-t.k*accepts)/(requests+1))
+}
+
+// admit decides whether a call may run and counts it in the window when it
+// may not.
+func (t *throttle) admit(record *storage.Record, now time.Time) bool {
+	p := t.rejectProbability(*record, now)
+	if p > 0 && rand.Float64() < p {
+		t.window.reject(record, now)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shuklasaharsh/circuitbreaker/breakertest"
+	"github.com/shuklasaharsh/circuitbreaker/storage"
+)
+
+func TestThrottleRejectProbability(t *testing.T) {
+	th := newThrottle(2, 10*time.Second)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	record := storage.DefaultRecord()
+	if p := th.rejectProbability(record, now); p != 0 {
+		t.Fatalf("expected 0 for empty window, got %v", p)
+	}
+
+	// 9 calls with 2 accepted: (9 - 2*2) / (9 + 1) = 0.5.
+	for range 7 {
+		th.window.add(&record, storage.CallFailure, now)
+	}
+	for range 2 {
+		th.window.add(&record, storage.CallSuccess, now)
+	}
+	if p := th.rejectProbability(record, now); p != 0.5 {
+		t.Fatalf("expected 0.5, got %v", p)
+	}
+
+	// Rejected calls count as requests: (10 - 2*2) / (10 + 1).
+	th.window.reject(&record, now)
+	if p := th.rejectProbability(record, now); p != 6.0/11 {
+		t.Fatalf("expected 6/11, got %v", p)
+	}
+}
+
+func TestAdaptiveThrottlingAdmitsHealthyTraffic(t *testing.T) {
+	cb := New("svc", WithAdaptiveThrottling(2, time.Minute))
+	for i := range 200 {
+		if err := cb.Execute(func() error { return nil }); err != nil {
+			t.Fatalf("call %d rejected: %v", i, err)
+		}
+	}
+}
+
+func TestAdaptiveThrottlingRejectsFailingTraffic(t *testing.T) {
+	cb := New("svc", WithAdaptiveThrottling(2, time.Minute))
+	boom := errors.New("boom")
+	for range 100 {
+		_ = cb.Execute(func() error { return boom })
+	}
+
+	rejected := 0
+	for range 100 {
+		if errors.Is(cb.Execute(func() error { return boom }), ErrCircuitOpen) {
+			rejected++
+		}
+	}
+	if rejected < 90 {
+		t.Fatalf("expected most calls rejected, got %d of 100", rejected)
+	}
+
+	record, err := cb.Snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("snapshot error: %v", err)
+	}
+	if record.State != StateClosed {
+		t.Fatalf("expected throttling breaker to stay closed, got %v", record.State)
+	}
+	if record.Failures == 0 {
+		t.Fatalf("expected failures in snapshot")
+	}
+}
+
+func TestAdaptiveThrottlingForgetsExpiredCalls(t *testing.T) {
+	clock := breakertest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cb := New("svc", WithAdaptiveThrottling(2, 10*time.Second), WithClock(clock))
+	for range 100 {
+		_ = cb.Execute(func() error { return errors.New("boom") })
+	}
+
+	clock.Advance(11 * time.Second)
+	for i := range 50 {
+		if err := cb.Execute(func() error { return nil }); err != nil {
+			t.Fatalf("call %d rejected after window expired: %v", i, err)
+		}
+	}
+}
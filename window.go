@@ -0,0 +1,138 @@
+package breaker
+
+import (
+	"time"
+
+	"github.com/shuklasaharsh/circuitbreaker/storage"
+)
+
+// window keeps recent call outcomes in the record for rate based tripping.
+type window interface {
+	add(record *storage.Record, result storage.CallResult, now time.Time)
+	stats(record storage.Record, now time.Time) windowStats
+	reset(record *storage.Record)
+}
+
+type windowStats struct {
+	calls    int64
+	failures int64
+	slow     int64
+	rejected int64
+}
+
+func (s windowStats) failureRate() float64 {
+	return s.rate(s.failures)
+}
+
+func (s windowStats) slowCallRate() float64 {
+	return s.rate(s.slow)
+}
+
+func (s windowStats) rate(n int64) float64 {
+	if s.calls == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(s.calls)
+}
+
+// countWindow keeps the outcomes of the last size calls.
+type countWindow struct {
+	size int64
+}
+
+func (w countWindow) add(record *storage.Record, result storage.CallResult, _ time.Time) {
+	calls := record.Calls
+	if int64(len(calls)) >= w.size {
+		calls = calls[int64(len(calls))-w.size+1:]
+	}
+	// Copy so records handed out by a store never share a backing array.
+	next := make([]storage.CallResult, 0, len(calls)+1)
+	next = append(next, calls...)
+	record.Calls = append(next, result)
+}
+
+func (w countWindow) stats(record storage.Record, _ time.Time) windowStats {
+	var s windowStats
+	for _, result := range record.Calls {
+		s.calls++
+		if result&storage.CallFailure != 0 {
+			s.failures++
+		}
+		if result&storage.CallSlow != 0 {
+			s.slow++
+		}
+	}
+	return s
+}
+
+func (countWindow) reset(record *storage.Record) {
+	record.Calls = nil
+}
+
+// timeWindow aggregates calls into fixed-width buckets covering the last span.
+type timeWindow struct {
+	span  time.Duration
+	width time.Duration
+}
+
+func newTimeWindow(span time.Duration, buckets int64) timeWindow {
+	return timeWindow{span: span, width: span / time.Duration(buckets)}
+}
+
+func (w timeWindow) add(record *storage.Record, result storage.CallResult, now time.Time) {
+	current := w.current(record, now)
+	current.Calls++
+	if result&storage.CallFailure != 0 {
+		current.Failures++
+	}
+	if result&storage.CallSlow != 0 {
+		current.SlowCalls++
+	}
+}
+
+// reject counts a call that was turned away without running.
+func (w timeWindow) reject(record *storage.Record, now time.Time) {
+	w.current(record, now).Rejected++
+}
+
+// current drops expired buckets from the record and returns the bucket for
+// now, creating it if needed. The buckets are copied so records handed out by
+// a store never share a backing array.
+func (w timeWindow) current(record *storage.Record, now time.Time) *storage.Bucket {
+	live := w.live(record.Buckets, now)
+	start := now.Truncate(w.width)
+
+	next := make([]storage.Bucket, 0, len(live)+1)
+	next = append(next, live...)
+	if n := len(next); n == 0 || !next[n-1].Start.Equal(start) {
+		next = append(next, storage.Bucket{Start: start})
+	}
+	record.Buckets = next
+	return &next[len(next)-1]
+}
+
+func (w timeWindow) stats(record storage.Record, now time.Time) windowStats {
+	var s windowStats
+	for _, bucket := range w.live(record.Buckets, now) {
+		s.calls += bucket.Calls
+		s.failures += bucket.Failures
+		s.slow += bucket.SlowCalls
+		s.rejected += bucket.Rejected
+	}
+	return s
+}
+
+func (timeWindow) reset(record *storage.Record) {
+	record.Buckets = nil
+}
+
+// live returns the buckets that still overlap the window ending at now.
+func (w timeWindow) live(buckets []storage.Bucket, now time.Time) []storage.Bucket {
+	cutoff := now.Truncate(w.width).Add(-w.span)
+	for i, bucket := range buckets {
+		if bucket.Start.After(cutoff) {
+			return buckets[i:]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shuklasaharsh/circuitbreaker/storage"
+)
+
+func TestCountWindowDropsOldest(t *testing.T) {
+	w := countWindow{size: 3}
+	record := storage.DefaultRecord()
+	w.add(&record, storage.CallFailure, time.Time{})
+	w.add(&record, storage.CallSuccess, time.Time{})
+	w.add(&record, storage.CallSuccess, time.Time{})
+	w.add(&record, storage.CallSuccess, time.Time{})
+
+	stats := w.stats(record, time.Time{})
+	if stats.calls != 3 || stats.failures != 0 {
+		t.Fatalf("expected calls=3 failures=0, got calls=%d failures=%d", stats.calls, stats.failures)
+	}
+}
+
+func TestCountWindowDoesNotShareBackingArray(t *testing.T) {
+	w := countWindow{size: 4}
+	record := storage.DefaultRecord()
+	w.add(&record, storage.CallSuccess, time.Time{})
+	before := record
+
+	w.add(&record, storage.CallFailure, time.Time{})
+	if len(before.Calls) != 1 || before.Calls[0] != storage.CallSuccess {
+		t.Fatalf("previous record mutated: %v", before.Calls)
+	}
+}
+
+func TestWindowStatsFailureRate(t *testing.T) {
+	if rate := (windowStats{}).failureRate(); rate != 0 {
+		t.Fatalf("expected 0 for empty window, got %v", rate)
+	}
+	if rate := (windowStats{calls: 4, failures: 1}).failureRate(); rate != 25 {
+		t.Fatalf("expected 25, got %v", rate)
+	}
+}
+
+func TestCountWindowReset(t *testing.T) {
+	w := countWindow{size: 2}
+	record := storage.DefaultRecord()
+	w.add(&record, storage.CallFailure, time.Time{})
+	w.reset(&record)
+	if len(record.Calls) != 0 {
+		t.Fatalf("expected empty window, got %v", record.Calls)
+	}
+}
+
+func TestTimeWindowAggregatesIntoBuckets(t *testing.T) {
+	w := newTimeWindow(10*time.Second, 10)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	record := storage.DefaultRecord()
+	w.add(&record, storage.CallFailure, base)
+	w.add(&record, storage.CallSuccess, base.Add(500*time.Millisecond))
+	w.add(&record, storage.CallFailure, base.Add(1500*time.Millisecond))
+
+	if len(record.Buckets) != 2 {
+		t.Fatalf("expected 2 buckets, got %d", len(record.Buckets))
+	}
+	stats := w.stats(record, base.Add(2*time.Second))
+	if stats.calls != 3 || stats.failures != 2 {
+		t.Fatalf("expected calls=3 failures=2, got calls=%d failures=%d", stats.calls, stats.failures)
+	}
+}
+
+func TestTimeWindowDropsExpiredBuckets(t *testing.T) {
+	w := newTimeWindow(10*time.Second, 10)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	record := storage.DefaultRecord()
+	w.add(&record, storage.CallFailure, base)
+	w.add(&record, storage.CallFailure, base.Add(5*time.Second))
+
+	stats := w.stats(record, base.Add(10*time.Second))
+	if stats.calls != 1 || stats.failures != 1 {
+		t.Fatalf("expected oldest bucket to expire, got calls=%d failures=%d", stats.calls, stats.failures)
+	}
+
+	w.add(&record, storage.CallSuccess, base.Add(20*time.Second))
+	if len(record.Buckets) != 1 {
+		t.Fatalf("expected expired buckets pruned, got %d", len(record.Buckets))
+	}
+}
+
+func TestTimeWindowReset(t *testing.T) {
+	w := newTimeWindow(time.Second, 2)
+	record := storage.DefaultRecord()
+	w.add(&record, storage.CallFailure, time.Now())
+	w.reset(&record)
+	if len(record.Buckets) != 0 {
+		t.Fatalf("expected empty window, got %v", record.Buckets)
+	}
+}
+
+func TestWindowsCountSlowCalls(t *testing.T) {
+	now := time.Now()
+	for _, w := range []window{countWindow{size: 4}, newTimeWindow(time.Minute, 6)} {
+		record := storage.DefaultRecord()
+		w.add(&record, storage.CallSuccess|storage.CallSlow, now)
+		w.add(&record, storage.CallFailure|storage.CallSlow, now)
+		w.add(&record, storage.CallSuccess, now)
+
+		stats := w.stats(record, now)
+		if stats.calls != 3 || stats.failures != 1 || stats.slow != 2 {
+			t.Fatalf("%T: unexpected stats %+v", w, stats)
+		}
+	}
+}
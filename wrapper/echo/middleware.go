@@ -1,6 +1,7 @@
 package echo
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -24,6 +25,18 @@ func WithOnRejected(fn func(echoapi.Context, error) error) Option {
 	}
 }
 
+// WithFallback serves h instead of a 503 when the circuit is open, for example
+// a cached or degraded response. It replaces the rejection handler.
+func WithFallback(h echoapi.HandlerFunc) Option {
+	return func(cfg *config) {
+		if h != nil {
+			cfg.onRejected = func(c echoapi.Context, _ error) error {
+				return h(c)
+			}
+		}
+	}
+}
+
 func WithOnError(fn func(echoapi.Context, error) error) Option {
 	return func(cfg *config) {
 		if fn != nil {
@@ -58,9 +71,16 @@ func middlewareWithBreaker(b *breaker.Breaker, opts ...Option) echoapi.Middlewar
 
 	return func(next echoapi.HandlerFunc) echoapi.HandlerFunc {
 		return func(c echoapi.Context) error {
-			err := b.ExecuteContext(c.Request().Context(), func() error {
+			parent := c.Request().Context()
+			err := b.ExecuteWithFallback(parent, func(ctx context.Context) error {
+				// The breaker cancels ctx once the call returns, so middleware
+				// running after this one gets the original context back.
+				c.SetRequest(c.Request().WithContext(ctx))
+				defer func() {
+					c.SetRequest(c.Request().WithContext(parent))
+				}()
 				return next(c)
-			})
+			}, passthrough)
 			if err == nil {
 				return nil
 			}
@@ -72,6 +92,12 @@ func middlewareWithBreaker(b *breaker.Breaker, opts ...Option) echoapi.Middlewar
 	}
 }
 
+// passthrough keeps rejections and failures visible to the middleware instead
+// of a breaker-level fallback, which cannot write a response.
+func passthrough(_ context.Context, err error) error {
+	return err
+}
+
 func defaultConfig() config {
 	return config{
 		onRejected: func(c echoapi.Context, _ error) error {
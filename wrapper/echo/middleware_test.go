@@ -137,3 +137,124 @@ func TestMiddlewareOnErrorCalled(t *testing.T) {
 		t.Fatalf("expected 520, got %d", rec.Code)
 	}
 }
+
+func TestMiddlewarePanicReturnErrorCallsOnError(t *testing.T) {
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithPanicPolicy(breaker.PanicReturnError),
+	)
+	var got error
+	middleware, _ := MiddlewareWithBreaker(cb, WithOnError(func(c echoapi.Context, err error) error {
+		got = err
+		return c.NoContent(http.StatusInternalServerError)
+	}))
+
+	e := echoapi.New()
+	e.Use(middleware)
+	e.GET("/panic", func(echoapi.Context) error {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var panicErr *breaker.PanicError
+	if !errors.As(got, &panicErr) {
+		t.Fatalf("expected *PanicError, got %v", got)
+	}
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+	state, err := cb.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != breaker.StateOpen {
+		t.Fatalf("expected open, got %v", state)
+	}
+}
+
+func TestMiddlewarePanicRepanics(t *testing.T) {
+	cb := breaker.New("svc", breaker.WithFailureThreshold(1))
+	middleware, _ := MiddlewareWithBreaker(cb)
+
+	e := echoapi.New()
+	e.Use(middleware)
+	e.GET("/panic", func(echoapi.Context) error {
+		panic("boom")
+	})
+
+	func() {
+		defer func() {
+			if v := recover(); v != "boom" {
+				t.Fatalf("expected original panic value, got %v", v)
+			}
+		}()
+		req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+
+	state, err := cb.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != breaker.StateOpen {
+		t.Fatalf("expected open, got %v", state)
+	}
+}
+
+func TestMiddlewareServesFallbackWhenOpen(t *testing.T) {
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithTimeout(time.Minute),
+		breaker.WithFallback(func(context.Context, error) error { return nil }),
+	)
+	_ = cb.Execute(func() error { return errors.New("boom") })
+
+	middleware, _ := MiddlewareWithBreaker(cb, WithFallback(func(c echoapi.Context) error {
+		return c.NoContent(http.StatusNonAuthoritativeInfo)
+	}))
+
+	e := echoapi.New()
+	e.Use(middleware)
+	e.GET("/health", func(echoapi.Context) error {
+		t.Fatalf("handler should not run when circuit is open")
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNonAuthoritativeInfo {
+		t.Fatalf("expected fallback 203, got %d", rec.Code)
+	}
+}
+
+func TestMiddlewareRestoresRequestContext(t *testing.T) {
+	cb := breaker.New("svc", breaker.WithCallTimeout(time.Minute))
+	middleware, _ := MiddlewareWithBreaker(cb)
+
+	var outerErr error
+	e := echoapi.New()
+	e.Use(func(next echoapi.HandlerFunc) echoapi.HandlerFunc {
+		return func(c echoapi.Context) error {
+			err := next(c)
+			outerErr = c.Request().Context().Err()
+			return err
+		}
+	})
+	e.Use(middleware)
+	e.GET("/health", func(c echoapi.Context) error {
+		if _, ok := c.Request().Context().Deadline(); !ok {
+			t.Fatalf("expected the handler to get the call deadline")
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	if outerErr != nil {
+		t.Fatalf("expected outer middleware to see a live context, got %v", outerErr)
+	}
+}
@@ -9,4 +9,9 @@ var (
 	ErrInvalidBreakerName = errors.NewError(103, "breaker name cannot be empty", errors.ConfigError)
 	ErrBreakerNotFound    = errors.NewError(104, "breaker not found in registry", errors.ConfigError)
 	ErrInvalidRequest     = errors.NewError(105, "http request cannot be nil", errors.ConfigError)
+	ErrInvalidFallback    = errors.NewError(106, "fallback cannot be nil", errors.ConfigError)
+	ErrInvalidHook        = errors.NewError(107, "registration hook cannot be nil", errors.ConfigError)
+	ErrInvalidPolicy      = errors.NewError(108, "policy cannot be nil", errors.ConfigError)
+	ErrInvalidPolicyName  = errors.NewError(109, "policy name cannot be empty", errors.ConfigError)
+	ErrPolicyNotFound     = errors.NewError(110, "policy not found in registry", errors.ConfigError)
 )
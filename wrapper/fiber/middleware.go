@@ -36,6 +36,18 @@ func WithOnRejected(fn func(*fiberapi.Ctx, error) error) Option {
 	}
 }
 
+// WithFallback serves h instead of a 503 when the circuit is open, for example
+// a cached or degraded response. It replaces the rejection handler.
+func WithFallback(h fiberapi.Handler) Option {
+	return func(cfg *config) {
+		if h != nil {
+			cfg.onRejected = func(c *fiberapi.Ctx, _ error) error {
+				return h(c)
+			}
+		}
+	}
+}
+
 func WithOnError(fn func(*fiberapi.Ctx, error) error) Option {
 	return func(cfg *config) {
 		if fn != nil {
@@ -73,9 +85,18 @@ func middlewareWithBreaker(b *breaker.Breaker, opts ...Option) fiberapi.Handler
 		if ctx == nil {
 			ctx = context.Background()
 		}
-		err := b.ExecuteContext(ctx, func() error {
+		err := b.ExecuteWithFallback(ctx, func(callCtx context.Context) error {
+			// Only hand the handler a context the breaker derived, so a user
+			// context set by earlier middleware is left alone.
+			// The breaker cancels callCtx once the call returns, so the user
+			// context is restored for middleware running after this one.
+			if callCtx != ctx {
+				prev := c.UserContext()
+				c.SetUserContext(callCtx)
+				defer c.SetUserContext(prev)
+			}
 			return c.Next()
-		})
+		}, passthrough)
 		if err == nil {
 			return nil
 		}
@@ -86,6 +107,12 @@ func middlewareWithBreaker(b *breaker.Breaker, opts ...Option) fiberapi.Handler
 	}
 }
 
+// passthrough keeps rejections and failures visible to the middleware instead
+// of a breaker-level fallback, which cannot write a response.
+func passthrough(_ context.Context, err error) error {
+	return err
+}
+
 func defaultConfig() config {
 	return config{
 		contextFunc: func(_ *fiberapi.Ctx) context.Context {
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	fiberapi "github.com/gofiber/fiber/v2"
+	fiberrecover "github.com/gofiber/fiber/v2/middleware/recover"
 	breaker "github.com/shuklasaharsh/circuitbreaker"
 	"github.com/shuklasaharsh/circuitbreaker/storage"
 	"github.com/shuklasaharsh/circuitbreaker/wrapper"
@@ -166,3 +167,126 @@ func TestMiddlewareOnErrorCalled(t *testing.T) {
 		t.Fatalf("expected 520, got %d", resp.StatusCode)
 	}
 }
+
+func TestMiddlewarePanicReturnErrorCallsOnError(t *testing.T) {
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithPanicPolicy(breaker.PanicReturnError),
+	)
+	var got error
+	middleware, _ := MiddlewareWithBreaker(cb, WithOnError(func(c *fiberapi.Ctx, err error) error {
+		got = err
+		return c.SendStatus(http.StatusInternalServerError)
+	}))
+
+	app := fiberapi.New()
+	app.Use(middleware)
+	app.Get("/panic", func(*fiberapi.Ctx) error {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	var panicErr *breaker.PanicError
+	if !errors.As(got, &panicErr) {
+		t.Fatalf("expected *PanicError, got %v", got)
+	}
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", resp.StatusCode)
+	}
+	state, err := cb.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != breaker.StateOpen {
+		t.Fatalf("expected open, got %v", state)
+	}
+}
+
+func TestMiddlewarePanicRepanics(t *testing.T) {
+	cb := breaker.New("svc", breaker.WithFailureThreshold(1))
+	middleware, _ := MiddlewareWithBreaker(cb)
+
+	app := fiberapi.New()
+	app.Use(fiberrecover.New())
+	app.Use(middleware)
+	app.Get("/panic", func(*fiberapi.Ctx) error {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected recover middleware to handle panic, got %d", resp.StatusCode)
+	}
+	state, err := cb.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != breaker.StateOpen {
+		t.Fatalf("expected open, got %v", state)
+	}
+}
+
+func TestMiddlewareServesFallbackWhenOpen(t *testing.T) {
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithTimeout(time.Minute),
+		breaker.WithFallback(func(context.Context, error) error { return nil }),
+	)
+	_ = cb.Execute(func() error { return errors.New("boom") })
+
+	middleware, _ := MiddlewareWithBreaker(cb, WithFallback(func(c *fiberapi.Ctx) error {
+		return c.SendStatus(http.StatusNonAuthoritativeInfo)
+	}))
+
+	app := fiberapi.New()
+	app.Use(middleware)
+	app.Get("/health", func(*fiberapi.Ctx) error {
+		t.Fatalf("handler should not run when circuit is open")
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	if resp.StatusCode != http.StatusNonAuthoritativeInfo {
+		t.Fatalf("expected fallback 203, got %d", resp.StatusCode)
+	}
+}
+
+func TestMiddlewareRestoresUserContext(t *testing.T) {
+	cb := breaker.New("svc", breaker.WithCallTimeout(time.Minute))
+	middleware, _ := MiddlewareWithBreaker(cb)
+
+	var outerErr error
+	app := fiberapi.New()
+	app.Use(func(c *fiberapi.Ctx) error {
+		err := c.Next()
+		outerErr = c.UserContext().Err()
+		return err
+	})
+	app.Use(middleware)
+	app.Get("/health", func(c *fiberapi.Ctx) error {
+		if _, ok := c.UserContext().Deadline(); !ok {
+			t.Fatalf("expected the handler to get the call deadline")
+		}
+		return c.SendStatus(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	if _, err := app.Test(req, -1); err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	if outerErr != nil {
+		t.Fatalf("expected outer middleware to see a live context, got %v", outerErr)
+	}
+}
@@ -1,6 +1,7 @@
 package gin
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -35,6 +36,19 @@ func WithOnRejected(fn func(*gingonic.Context, error)) Option {
 	}
 }
 
+// WithFallback serves h instead of a 503 when the circuit is open, for example
+// a cached or degraded response. It replaces the rejection handler.
+func WithFallback(h gingonic.HandlerFunc) Option {
+	return func(cfg *config) {
+		if h != nil {
+			cfg.onRejected = func(c *gingonic.Context, _ error) {
+				h(c)
+				c.Abort()
+			}
+		}
+	}
+}
+
 func WithOnError(fn func(*gingonic.Context, error)) Option {
 	return func(cfg *config) {
 		if fn != nil {
@@ -68,13 +82,20 @@ func middlewareWithBreaker(b *breaker.Breaker, opts ...Option) gingonic.HandlerF
 	}
 
 	return func(c *gingonic.Context) {
-		err := b.ExecuteContext(c.Request.Context(), func() error {
+		parent := c.Request.Context()
+		err := b.ExecuteWithFallback(parent, func(ctx context.Context) error {
+			// The breaker cancels ctx once the call returns, so middleware
+			// running after this one gets the original context back.
+			c.Request = c.Request.WithContext(ctx)
+			defer func() {
+				c.Request = c.Request.WithContext(parent)
+			}()
 			c.Next()
 			if c.Writer.Status() >= cfg.failureStatusCode {
 				return errFailureStatus
 			}
 			return nil
-		})
+		}, passthrough)
 		if err == nil {
 			return
 		}
@@ -89,6 +110,12 @@ func middlewareWithBreaker(b *breaker.Breaker, opts ...Option) gingonic.HandlerF
 	}
 }
 
+// passthrough keeps rejections and failures visible to the middleware instead
+// of a breaker-level fallback, which cannot write a response.
+func passthrough(_ context.Context, err error) error {
+	return err
+}
+
 func defaultConfig() config {
 	return config{
 		failureStatusCode: http.StatusInternalServerError,
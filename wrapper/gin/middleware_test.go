@@ -142,3 +142,116 @@ func TestMiddlewareOnErrorCalled(t *testing.T) {
 		t.Fatalf("expected 520, got %d", rec.Code)
 	}
 }
+
+func TestMiddlewarePanicReturnErrorCallsOnError(t *testing.T) {
+	gingonic.SetMode(gingonic.TestMode)
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithPanicPolicy(breaker.PanicReturnError),
+	)
+	middleware, _ := MiddlewareWithBreaker(cb)
+
+	router := gingonic.New()
+	router.Use(middleware)
+	router.GET("/panic", func(*gingonic.Context) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+	state, err := cb.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != breaker.StateOpen {
+		t.Fatalf("expected open, got %v", state)
+	}
+}
+
+func TestMiddlewarePanicRepanics(t *testing.T) {
+	gingonic.SetMode(gingonic.TestMode)
+	cb := breaker.New("svc", breaker.WithFailureThreshold(1))
+	middleware, _ := MiddlewareWithBreaker(cb)
+
+	router := gingonic.New()
+	router.Use(gingonic.CustomRecovery(func(c *gingonic.Context, _ any) {
+		c.AbortWithStatus(http.StatusBadGateway)
+	}))
+	router.Use(middleware)
+	router.GET("/panic", func(*gingonic.Context) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("expected recovery middleware to handle panic, got %d", rec.Code)
+	}
+	state, err := cb.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != breaker.StateOpen {
+		t.Fatalf("expected open, got %v", state)
+	}
+}
+
+func TestMiddlewareServesFallbackWhenOpen(t *testing.T) {
+	gingonic.SetMode(gingonic.TestMode)
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithTimeout(time.Minute),
+		breaker.WithFallback(func(context.Context, error) error { return nil }),
+	)
+	_ = cb.Execute(func() error { return errors.New("boom") })
+
+	middleware, _ := MiddlewareWithBreaker(cb, WithFallback(func(c *gingonic.Context) {
+		c.Status(http.StatusNonAuthoritativeInfo)
+	}))
+
+	router := gingonic.New()
+	router.Use(middleware)
+	router.GET("/health", func(*gingonic.Context) {
+		t.Fatalf("handler should not run when circuit is open")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNonAuthoritativeInfo {
+		t.Fatalf("expected fallback 203, got %d", rec.Code)
+	}
+}
+
+func TestMiddlewareRestoresRequestContext(t *testing.T) {
+	gingonic.SetMode(gingonic.TestMode)
+	cb := breaker.New("svc", breaker.WithCallTimeout(time.Minute))
+	middleware, _ := MiddlewareWithBreaker(cb)
+
+	var outerErr error
+	router := gingonic.New()
+	router.Use(func(c *gingonic.Context) {
+		c.Next()
+		outerErr = c.Request.Context().Err()
+	})
+	router.Use(middleware)
+	router.GET("/health", func(c *gingonic.Context) {
+		if _, ok := c.Request.Context().Deadline(); !ok {
+			t.Fatalf("expected the handler to get the call deadline")
+		}
+		c.Status(http.StatusOK)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	if outerErr != nil {
+		t.Fatalf("expected outer middleware to see a live context, got %v", outerErr)
+	}
+}
@@ -1,14 +1,19 @@
 package wrapper
 
 import (
+	"context"
+	"io"
 	"net/http"
 
 	breaker "github.com/shuklasaharsh/circuitbreaker"
+	"github.com/shuklasaharsh/circuitbreaker/policy"
 )
 
 type HttpWrapper struct {
 	httpClient *http.Client
 	breaker    *breaker.Breaker
+	fallback   func(*http.Request, error) (*http.Response, error)
+	policy     *policy.Policy
 }
 
 func NewHttpWrapper(httpClient *http.Client) *HttpWrapper {
@@ -38,22 +43,144 @@ func (w *HttpWrapper) SetBreakerFromRegistry(reg *Registry, name string) error {
 	return nil
 }
 
+// SetPolicy sends requests through p instead of the breaker. p can include
+// the breaker along with retries, timeouts and a bulkhead.
+func (w *HttpWrapper) SetPolicy(p *policy.Policy) error {
+	if p == nil {
+		return ErrInvalidPolicy
+	}
+	w.policy = p
+	return nil
+}
+
+func (w *HttpWrapper) SetPolicyFromRegistry(reg *Registry, name string) error {
+	if reg == nil {
+		return ErrInvalidRegistry
+	}
+	p, err := reg.Policy(name)
+	if err != nil {
+		return err
+	}
+	w.policy = p
+	return nil
+}
+
+// SetFallback serves fn's response when the circuit is open or the request
+// fails, for example a cached or degraded response.
+func (w *HttpWrapper) SetFallback(fn func(*http.Request, error) (*http.Response, error)) error {
+	if fn == nil {
+		return ErrInvalidFallback
+	}
+	w.fallback = fn
+	return nil
+}
+
 func (w *HttpWrapper) Do(req *http.Request) (*http.Response, error) {
 	if req == nil {
 		return nil, ErrInvalidRequest
 	}
+	if w.policy != nil {
+		return w.doPolicy(req)
+	}
 	if w.breaker == nil {
 		return nil, ErrInvalidBreaker
 	}
 
+	do := func(ctx context.Context) (*http.Response, error) {
+		return w.send(req.WithContext(ctx), req.Context())
+	}
+	if w.fallback != nil {
+		return breaker.RunWithFallback(req.Context(), w.breaker, do, func(_ context.Context, err error) (*http.Response, error) {
+			return w.fallback(req, err)
+		})
+	}
+
+	resp, err := breaker.Run(req.Context(), w.breaker, do)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (w *HttpWrapper) doPolicy(req *http.Request) (*http.Response, error) {
+	// Every attempt reads a fresh copy of the body, so the original is only
+	// closed.
+	replay := req.Body != nil && req.Body != http.NoBody && req.GetBody != nil
+	if replay {
+		defer req.Body.Close()
+	}
 	var resp *http.Response
-	err := w.breaker.ExecuteContext(req.Context(), func() error {
+	err := w.policy.Execute(req.Context(), func(ctx context.Context) error {
+		if resp != nil {
+			// An earlier attempt was rejected after it returned a response.
+			resp.Body.Close()
+			resp = nil
+		}
+		attempt := req.WithContext(ctx)
+		if replay {
+			body, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			attempt.Body = body
+		}
 		var err error
-		resp, err = w.httpClient.Do(req)
+		resp, err = w.send(attempt, req.Context())
 		return err
 	})
 	if err != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if w.fallback != nil {
+			return w.fallback(req, err)
+		}
 		return nil, err
 	}
 	return resp, nil
 }
+
+// send performs req, whose context is the call context handed out by the
+// breaker. That context is cancelled as soon as the call returns, which would
+// leave the response body unreadable, so the request runs on a context that
+// keeps its values and deadline but is cancelled only by the caller's context
+// or by closing the response body.
+func (w *HttpWrapper) send(req *http.Request, caller context.Context) (*http.Response, error) {
+	ctx, cancel := detach(req.Context(), caller)
+	resp, err := w.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// detach returns a context with the values and deadline of ctx that is not
+// cancelled with it, but is cancelled with caller.
+func detach(ctx, caller context.Context) (context.Context, context.CancelFunc) {
+	var detached context.Context
+	var cancel context.CancelFunc
+	if deadline, ok := ctx.Deadline(); ok {
+		detached, cancel = context.WithDeadline(context.WithoutCancel(ctx), deadline)
+	} else {
+		detached, cancel = context.WithCancel(context.WithoutCancel(ctx))
+	}
+	stop := context.AfterFunc(caller, cancel)
+	return detached, func() {
+		stop()
+		cancel()
+	}
+}
+
+// cancelOnClose releases the request context once the body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -1,13 +1,18 @@
 package wrapper
 
 import (
+	"context"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	breaker "github.com/shuklasaharsh/circuitbreaker"
+	"github.com/shuklasaharsh/circuitbreaker/policy"
+	"github.com/shuklasaharsh/circuitbreaker/retry"
 )
 
 func TestNewHttpWrapperNilPanics(t *testing.T) {
@@ -98,3 +103,233 @@ func TestHttpWrapperSetBreakerFromRegistry(t *testing.T) {
 		t.Fatalf("set breaker error: %v", err)
 	}
 }
+
+func TestHttpWrapperDoAppliesCallTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithCallTimeout(10*time.Millisecond),
+	)
+	w := NewHttpWrapper(&http.Client{})
+	if err := w.SetBreaker(cb); err != nil {
+		t.Fatalf("set breaker error: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	if _, err := w.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+	state, err := cb.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != breaker.StateOpen {
+		t.Fatalf("expected open, got %v", state)
+	}
+}
+
+func TestHttpWrapperSetFallbackNil(t *testing.T) {
+	w := NewHttpWrapper(&http.Client{})
+	if err := w.SetFallback(nil); err != ErrInvalidFallback {
+		t.Fatalf("expected ErrInvalidFallback, got %v", err)
+	}
+}
+
+func TestHttpWrapperDoServesFallbackWhenOpen(t *testing.T) {
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithTimeout(time.Minute),
+	)
+	_ = cb.Execute(func() error { return errors.New("boom") })
+
+	w := NewHttpWrapper(&http.Client{})
+	if err := w.SetBreaker(cb); err != nil {
+		t.Fatalf("set breaker error: %v", err)
+	}
+	if err := w.SetFallback(func(req *http.Request, err error) (*http.Response, error) {
+		if !errors.Is(err, breaker.ErrCircuitOpen) {
+			t.Fatalf("expected ErrCircuitOpen in fallback, got %v", err)
+		}
+		return &http.Response{StatusCode: http.StatusNonAuthoritativeInfo, Request: req}, nil
+	}); err != nil {
+		t.Fatalf("set fallback error: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := w.Do(req)
+	if err != nil {
+		t.Fatalf("do error: %v", err)
+	}
+	if resp.StatusCode != http.StatusNonAuthoritativeInfo {
+		t.Fatalf("expected 203, got %d", resp.StatusCode)
+	}
+}
+
+func TestHttpWrapperSetPolicyNil(t *testing.T) {
+	w := NewHttpWrapper(&http.Client{})
+	if err := w.SetPolicy(nil); err != ErrInvalidPolicy {
+		t.Fatalf("expected ErrInvalidPolicy, got %v", err)
+	}
+	if err := w.SetPolicyFromRegistry(nil, "svc"); err != ErrInvalidRegistry {
+		t.Fatalf("expected ErrInvalidRegistry, got %v", err)
+	}
+	if err := w.SetPolicyFromRegistry(NewRegistry(), "missing"); err != ErrPolicyNotFound {
+		t.Fatalf("expected ErrPolicyNotFound, got %v", err)
+	}
+}
+
+func TestHttpWrapperDoThroughPolicyResendsBody(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Fail the first attempt after it reached the server, so it is retried.
+	attempts := 0
+	failFirst := policy.StrategyFunc(func(ctx context.Context, fn func(context.Context) error) error {
+		attempts++
+		if err := fn(ctx); err != nil || attempts > 1 {
+			return err
+		}
+		return errors.New("retry me")
+	})
+	reg := NewRegistry()
+	_ = reg.RegisterPolicy("svc", policy.New(
+		retry.New(retry.WithBackoff(0, 0, 1)),
+		breaker.New("svc"),
+		failFirst,
+	))
+	w := NewHttpWrapper(&http.Client{Timeout: time.Second})
+	if err := w.SetPolicyFromRegistry(reg, "svc"); err != nil {
+		t.Fatalf("set policy error: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	resp, err := w.Do(req)
+	if err != nil {
+		t.Fatalf("do error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if len(bodies) != 2 || bodies[0] != "payload" || bodies[1] != "payload" {
+		t.Fatalf("expected the body on both attempts, got %q", bodies)
+	}
+}
+
+// streamingServer sends headers before the body, so the body is still being
+// read after Do returns.
+func streamingServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(20 * time.Millisecond)
+		_, _ = io.WriteString(w, "payload")
+	}))
+}
+
+func TestHttpWrapperBodyReadableWithCallTimeout(t *testing.T) {
+	server := streamingServer()
+	defer server.Close()
+
+	w := NewHttpWrapper(&http.Client{})
+	_ = w.SetBreaker(breaker.New("svc", breaker.WithCallTimeout(time.Second)))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := w.Do(req)
+	if err != nil {
+		t.Fatalf("do error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	if string(body) != "payload" {
+		t.Fatalf("expected payload, got %q", body)
+	}
+}
+
+func TestHttpWrapperCallTimeoutStillApplies(t *testing.T) {
+	server := streamingServer()
+	defer server.Close()
+
+	w := NewHttpWrapper(&http.Client{})
+	_ = w.SetBreaker(breaker.New("svc", breaker.WithCallTimeout(5*time.Millisecond)))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := w.Do(req)
+	if err != nil {
+		t.Fatalf("do error: %v", err)
+	}
+	defer resp.Body.Close()
+	if _, err := io.ReadAll(resp.Body); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the call deadline to bound the body, got %v", err)
+	}
+}
+
+func TestHttpWrapperPolicyTimeoutBodyReadable(t *testing.T) {
+	server := streamingServer()
+	defer server.Close()
+
+	w := NewHttpWrapper(&http.Client{})
+	_ = w.SetPolicy(policy.New(policy.Timeout(5*time.Second), breaker.New("svc")))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := w.Do(req)
+	if err != nil {
+		t.Fatalf("do error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	if string(body) != "payload" {
+		t.Fatalf("expected payload, got %q", body)
+	}
+}
+
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestHttpWrapperPolicyClosesReplacedBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	w := NewHttpWrapper(&http.Client{})
+	_ = w.SetPolicy(policy.New(breaker.New("svc")))
+
+	original := &closeTracker{Reader: strings.NewReader("payload")}
+	req, _ := http.NewRequest(http.MethodPost, server.URL, original)
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("payload")), nil
+	}
+	resp, err := w.Do(req)
+	if err != nil {
+		t.Fatalf("do error: %v", err)
+	}
+	resp.Body.Close()
+	if !original.closed {
+		t.Fatalf("expected the original body to be closed")
+	}
+}
@@ -2,6 +2,7 @@ package mux
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"net"
 	"net/http"
@@ -37,6 +38,18 @@ func WithOnRejected(fn func(http.ResponseWriter, *http.Request, error)) Option {
 	}
 }
 
+// WithFallback serves h instead of a 503 when the circuit is open, for example
+// a cached or degraded response. It replaces the rejection handler.
+func WithFallback(h http.Handler) Option {
+	return func(cfg *config) {
+		if h != nil {
+			cfg.onRejected = func(w http.ResponseWriter, r *http.Request, _ error) {
+				h.ServeHTTP(w, r)
+			}
+		}
+	}
+}
+
 func WithOnError(fn func(http.ResponseWriter, *http.Request, error)) Option {
 	return func(cfg *config) {
 		if fn != nil {
@@ -72,13 +85,13 @@ func middlewareWithBreaker(b *breaker.Breaker, opts ...Option) gorillamux.Middle
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			recorder := newStatusRecorder(w)
-			err := b.ExecuteContext(r.Context(), func() error {
-				next.ServeHTTP(recorder, r)
+			err := b.ExecuteWithFallback(r.Context(), func(ctx context.Context) error {
+				next.ServeHTTP(recorder, r.WithContext(ctx))
 				if recorder.StatusCode() >= cfg.failureStatusCode {
 					return errFailureStatus
 				}
 				return nil
-			})
+			}, passthrough)
 			if err == nil {
 				return
 			}
@@ -94,6 +107,12 @@ func middlewareWithBreaker(b *breaker.Breaker, opts ...Option) gorillamux.Middle
 	}
 }
 
+// passthrough keeps rejections and failures visible to the middleware instead
+// of a breaker-level fallback, which cannot write a response.
+func passthrough(_ context.Context, err error) error {
+	return err
+}
+
 func defaultConfig() config {
 	return config{
 		failureStatusCode: http.StatusInternalServerError,
@@ -137,3 +137,116 @@ func TestMiddlewareOnErrorCalled(t *testing.T) {
 		t.Fatalf("expected 520, got %d", rec.Code)
 	}
 }
+
+func TestMiddlewarePanicReturnErrorCallsOnError(t *testing.T) {
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithPanicPolicy(breaker.PanicReturnError),
+	)
+	var got error
+	middleware, _ := MiddlewareWithBreaker(cb, WithOnError(func(w http.ResponseWriter, _ *http.Request, err error) {
+		got = err
+		http.Error(w, "panic", http.StatusInternalServerError)
+	}))
+
+	router := gorillamux.NewRouter()
+	router.Use(middleware)
+	router.HandleFunc("/panic", func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var panicErr *breaker.PanicError
+	if !errors.As(got, &panicErr) {
+		t.Fatalf("expected *PanicError, got %v", got)
+	}
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+	state, err := cb.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != breaker.StateOpen {
+		t.Fatalf("expected open, got %v", state)
+	}
+}
+
+func TestMiddlewarePanicRepanics(t *testing.T) {
+	cb := breaker.New("svc", breaker.WithFailureThreshold(1))
+	middleware, _ := MiddlewareWithBreaker(cb)
+
+	router := gorillamux.NewRouter()
+	router.Use(middleware)
+	router.HandleFunc("/panic", func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	func() {
+		defer func() {
+			if v := recover(); v != http.ErrAbortHandler {
+				t.Fatalf("expected original panic value, got %v", v)
+			}
+		}()
+		req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+
+	state, err := cb.State(context.Background())
+	if err != nil {
+		t.Fatalf("state error: %v", err)
+	}
+	if state != breaker.StateOpen {
+		t.Fatalf("expected open, got %v", state)
+	}
+}
+
+func TestMiddlewarePropagatesCallDeadline(t *testing.T) {
+	cb := breaker.New("svc", breaker.WithCallTimeout(time.Minute))
+	middleware, _ := MiddlewareWithBreaker(cb)
+
+	router := gorillamux.NewRouter()
+	router.Use(middleware)
+	hasDeadline := false
+	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline = r.Context().Deadline()
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !hasDeadline {
+		t.Fatalf("expected handler context to carry the call deadline")
+	}
+}
+
+func TestMiddlewareServesFallbackWhenOpen(t *testing.T) {
+	cb := breaker.New("svc",
+		breaker.WithFailureThreshold(1),
+		breaker.WithTimeout(time.Minute),
+		breaker.WithFallback(func(context.Context, error) error { return nil }),
+	)
+	_ = cb.Execute(func() error { return errors.New("boom") })
+
+	middleware, _ := MiddlewareWithBreaker(cb, WithFallback(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNonAuthoritativeInfo)
+	})))
+
+	router := gorillamux.NewRouter()
+	router.Use(middleware)
+	router.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) {
+		t.Fatalf("handler should not run when circuit is open")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNonAuthoritativeInfo {
+		t.Fatalf("expected fallback 203, got %d", rec.Code)
+	}
+}
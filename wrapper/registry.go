@@ -1,19 +1,32 @@
 package wrapper
 
 import (
+	"maps"
+	"slices"
 	"sync"
 
 	breaker "github.com/shuklasaharsh/circuitbreaker"
+	"github.com/shuklasaharsh/circuitbreaker/policy"
 )
 
 type Registry struct {
 	mu       sync.RWMutex
 	breakers map[string]*breaker.Breaker
+	hooks    []*registerHook
+	policies map[string]*policy.Policy
+
+	// deliver serializes hook calls so each hook sees registrations in order.
+	deliver sync.Mutex
+}
+
+type registerHook struct {
+	fn func(name string, b *breaker.Breaker)
 }
 
 func NewRegistry() *Registry {
 	return &Registry{
 		breakers: make(map[string]*breaker.Breaker),
+		policies: make(map[string]*policy.Policy),
 	}
 }
 
@@ -35,15 +48,58 @@ func (r *Registry) RegisterBreakerWithName(name string, b *breaker.Breaker) erro
 		return ErrInvalidBreakerName
 	}
 
+	r.deliver.Lock()
+	defer r.deliver.Unlock()
 	r.mu.Lock()
 	if r.breakers == nil {
 		r.breakers = make(map[string]*breaker.Breaker)
 	}
 	r.breakers[name] = b
+	hooks := r.hooks
 	r.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook.fn(name, b)
+	}
 	return nil
 }
 
+// OnRegister calls fn for every breaker already in the registry and for every
+// breaker registered until stop is called, including replacements under an
+// existing name. fn must not register breakers itself.
+func (r *Registry) OnRegister(fn func(name string, b *breaker.Breaker)) (stop func(), err error) {
+	if r == nil {
+		return nil, ErrInvalidRegistry
+	}
+	if fn == nil {
+		return nil, ErrInvalidHook
+	}
+
+	hook := &registerHook{fn: fn}
+	r.deliver.Lock()
+	defer r.deliver.Unlock()
+	r.mu.Lock()
+	existing := make(map[string]*breaker.Breaker, len(r.breakers))
+	maps.Copy(existing, r.breakers)
+	r.hooks = append(slices.Clone(r.hooks), hook)
+	r.mu.Unlock()
+
+	for name, b := range existing {
+		fn(name, b)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.mu.Lock()
+			r.hooks = slices.DeleteFunc(slices.Clone(r.hooks), func(h *registerHook) bool {
+				return h == hook
+			})
+			r.mu.Unlock()
+		})
+	}, nil
+}
+
 func (r *Registry) Breaker(name string) (*breaker.Breaker, error) {
 	if r == nil {
 		return nil, ErrInvalidRegistry
@@ -60,3 +116,40 @@ func (r *Registry) Breaker(name string) (*breaker.Breaker, error) {
 	}
 	return b, nil
 }
+
+func (r *Registry) RegisterPolicy(name string, p *policy.Policy) error {
+	if r == nil {
+		return ErrInvalidRegistry
+	}
+	if p == nil {
+		return ErrInvalidPolicy
+	}
+	if name == "" {
+		return ErrInvalidPolicyName
+	}
+
+	r.mu.Lock()
+	if r.policies == nil {
+		r.policies = make(map[string]*policy.Policy)
+	}
+	r.policies[name] = p
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *Registry) Policy(name string) (*policy.Policy, error) {
+	if r == nil {
+		return nil, ErrInvalidRegistry
+	}
+	if name == "" {
+		return nil, ErrInvalidPolicyName
+	}
+
+	r.mu.RLock()
+	p := r.policies[name]
+	r.mu.RUnlock()
+	if p == nil {
+		return nil, ErrPolicyNotFound
+	}
+	return p, nil
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	breaker "github.com/shuklasaharsh/circuitbreaker"
+	"github.com/shuklasaharsh/circuitbreaker/policy"
 )
 
 func TestRegistryRegisterAndLookup(t *testing.T) {
@@ -59,3 +60,68 @@ func TestRegistryBreakerInvalidName(t *testing.T) {
 		t.Fatalf("expected ErrInvalidBreakerName, got %v", err)
 	}
 }
+
+func TestRegistryOnRegister(t *testing.T) {
+	reg := NewRegistry()
+	_ = reg.RegisterBreaker(breaker.New("existing"))
+
+	var seen []string
+	stop, err := reg.OnRegister(func(name string, _ *breaker.Breaker) {
+		seen = append(seen, name)
+	})
+	if err != nil {
+		t.Fatalf("on register error: %v", err)
+	}
+	_ = reg.RegisterBreakerWithName("added", breaker.New("svc"))
+	stop()
+	_ = reg.RegisterBreaker(breaker.New("after-stop"))
+
+	if len(seen) != 2 || seen[0] != "existing" || seen[1] != "added" {
+		t.Fatalf("expected existing then added, got %v", seen)
+	}
+}
+
+func TestRegistryOnRegisterErrors(t *testing.T) {
+	var nilReg *Registry
+	if _, err := nilReg.OnRegister(func(string, *breaker.Breaker) {}); err != ErrInvalidRegistry {
+		t.Fatalf("expected ErrInvalidRegistry, got %v", err)
+	}
+	if _, err := NewRegistry().OnRegister(nil); err != ErrInvalidHook {
+		t.Fatalf("expected ErrInvalidHook, got %v", err)
+	}
+}
+
+func TestRegistryRegisterAndLookupPolicy(t *testing.T) {
+	reg := NewRegistry()
+	p := policy.New(breaker.New("svc"))
+	if err := reg.RegisterPolicy("svc", p); err != nil {
+		t.Fatalf("register error: %v", err)
+	}
+	got, err := reg.Policy("svc")
+	if err != nil {
+		t.Fatalf("lookup error: %v", err)
+	}
+	if got != p {
+		t.Fatalf("expected policy pointer match")
+	}
+}
+
+func TestRegistryPolicyErrors(t *testing.T) {
+	var nilReg *Registry
+	if err := nilReg.RegisterPolicy("svc", policy.New()); err != ErrInvalidRegistry {
+		t.Fatalf("expected ErrInvalidRegistry, got %v", err)
+	}
+	reg := NewRegistry()
+	if err := reg.RegisterPolicy("svc", nil); err != ErrInvalidPolicy {
+		t.Fatalf("expected ErrInvalidPolicy, got %v", err)
+	}
+	if err := reg.RegisterPolicy("", policy.New()); err != ErrInvalidPolicyName {
+		t.Fatalf("expected ErrInvalidPolicyName, got %v", err)
+	}
+	if _, err := reg.Policy("missing"); err != ErrPolicyNotFound {
+		t.Fatalf("expected ErrPolicyNotFound, got %v", err)
+	}
+	if _, err := reg.Policy(""); err != ErrInvalidPolicyName {
+		t.Fatalf("expected ErrInvalidPolicyName, got %v", err)
+	}
+}